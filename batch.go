@@ -0,0 +1,147 @@
+package set
+
+import (
+	"sync"
+	"time"
+)
+
+// BatchQueue is an asynchronous write path for a set. Inserts and removes
+// are queued and applied in batches, either every interval or as soon as
+// maxBatch distinct keys are pending. Queued operations on the same key are
+// coalesced so only the last one is applied; an insert followed by a remove
+// costs a single tree operation. An interval of zero or less disables the
+// timed flushes, leaving only those triggered by maxBatch.
+//
+// Readers go through Read and see the set as of the last applied batch.
+// Once a set is handed to a BatchQueue it must only be accessed through it.
+type BatchQueue[T any] struct {
+	set      *Set[T]
+	setMu    sync.RWMutex
+	flushMu  sync.Mutex
+	queueMu  sync.Mutex
+	pending  *Set[*queuedWrite[T]]
+	maxBatch int
+	wake     chan struct{}
+	stop     chan struct{}
+	stopped  chan struct{}
+	closing  sync.Once
+}
+
+type queuedWrite[T any] struct {
+	key    T
+	remove bool
+}
+
+// NewBatchQueue starts a batch queue applying writes to s
+func NewBatchQueue[T any](s *Set[T], maxBatch int, interval time.Duration) *BatchQueue[T] {
+	if maxBatch < 1 {
+		maxBatch = 1
+	}
+	q := &BatchQueue[T]{
+		set: s,
+		pending: NewSet(func(a, b *queuedWrite[T]) int {
+			return s.compare(a.key, b.key)
+		}),
+		maxBatch: maxBatch,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go q.run(interval)
+	return q
+}
+
+// Insert queues the insertion of key
+func (q *BatchQueue[T]) Insert(key T) {
+	q.enqueue(key, false)
+}
+
+// Remove queues the removal of key
+func (q *BatchQueue[T]) Remove(key T) {
+	q.enqueue(key, true)
+}
+
+// Pending returns the number of distinct keys waiting to be applied
+func (q *BatchQueue[T]) Pending() int {
+	q.queueMu.Lock()
+	defer q.queueMu.Unlock()
+	return q.pending.Size()
+}
+
+// Read calls fn with the set while holding the read lock, so fn sees a
+// consistent state between batches. fn must not retain ro.
+func (q *BatchQueue[T]) Read(fn func(ro ReadOnlySet[T])) {
+	q.setMu.RLock()
+	defer q.setMu.RUnlock()
+	fn(q.set)
+}
+
+// Flush applies all queued writes now
+func (q *BatchQueue[T]) Flush() {
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+	q.queueMu.Lock()
+	batch := q.pending
+	q.pending = batch.emptyCopy()
+	q.queueMu.Unlock()
+	if batch.IsEmpty() {
+		return
+	}
+	q.setMu.Lock()
+	defer q.setMu.Unlock()
+	for it := batch.Begin(); it.Valid(); it.Next() {
+		w := it.Value()
+		if w.remove {
+			q.set.Remove(w.key)
+		} else {
+			q.set.Insert(w.key)
+		}
+	}
+}
+
+// Close stops the background flusher and applies the remaining writes.
+// Calling it again does nothing.
+func (q *BatchQueue[T]) Close() {
+	q.closing.Do(func() {
+		close(q.stop)
+		<-q.stopped
+		q.Flush()
+	})
+}
+
+func (q *BatchQueue[T]) enqueue(key T, remove bool) {
+	key = q.set.canonical(key)
+	q.queueMu.Lock()
+	w := &queuedWrite[T]{key: key, remove: remove}
+	if node, inserted := q.pending.insert(w); !inserted {
+		node.key = w
+	}
+	full := q.pending.Size() >= q.maxBatch
+	q.queueMu.Unlock()
+	if full {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (q *BatchQueue[T]) run(interval time.Duration) {
+	defer close(q.stopped)
+	// A nil channel never fires, so without an interval only wakes flush
+	var ticks <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ticks:
+		case <-q.wake:
+		}
+		q.Flush()
+	}
+}
@@ -0,0 +1,47 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBatchQueue(t *testing.T) {
+	s := NewSet(cmp.Compare[int])
+	q := NewBatchQueue(s, 100, time.Hour)
+	q.Insert(1)
+	q.Insert(2)
+	q.Remove(1)
+	q.Insert(3)
+	if q.Pending() != 3 {
+		t.Fatalf("Pending() = %d, want 3", q.Pending())
+	}
+	q.Flush()
+	q.Read(func(ro ReadOnlySet[int]) {
+		if ro.Size() != 2 || ro.Contains(1) {
+			t.Fatalf("set holds %v after a flush", s.ToSlice())
+		}
+	})
+	q.Close()
+}
+
+func TestBatchQueueSizeOnly(t *testing.T) {
+	// Without an interval, batches are flushed when maxBatch is reached
+	s := NewSet(cmp.Compare[int])
+	q := NewBatchQueue(s, 2, 0)
+	q.Insert(1)
+	q.Insert(2)
+	for deadline := time.Now().Add(5 * time.Second); q.Pending() > 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("a full batch was never flushed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	q.Insert(3)
+	q.Close()
+	q.Close()
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("set holds %v after Close", got)
+	}
+}
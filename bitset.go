@@ -0,0 +1,124 @@
+package set
+
+import "math/bits"
+
+// BitSet is a set engine for dense integers within a fixed range, storing
+// one bit per possible value. Insert, Remove and Contains are single bit
+// operations and iteration scans whole words, which is far cheaper than
+// tree nodes when most of the range is populated.
+type BitSet struct {
+	words []uint64
+	lo    int
+	hi    int
+	size  int
+}
+
+// NewBitSet creates a bitset able to hold the integers in [lo, hi)
+func NewBitSet(lo, hi int) *BitSet {
+	if hi < lo {
+		hi = lo
+	}
+	return &BitSet{
+		words: make([]uint64, (hi-lo+63)/64),
+		lo:    lo,
+		hi:    hi,
+	}
+}
+
+// Size returns the number of elements in the set
+func (b *BitSet) Size() int {
+	return b.size
+}
+
+// IsEmpty returns true if the set has no elements
+func (b *BitSet) IsEmpty() bool {
+	return b.size == 0
+}
+
+// Clear removes all elements from the set
+func (b *BitSet) Clear() {
+	for i := range b.words {
+		b.words[i] = 0
+	}
+	b.size = 0
+}
+
+// Insert adds key to the set. It returns false if key is already present
+// or outside the range of the bitset.
+func (b *BitSet) Insert(key int) bool {
+	if key < b.lo || key >= b.hi {
+		return false
+	}
+	i, mask := b.bit(key)
+	if b.words[i]&mask != 0 {
+		return false
+	}
+	b.words[i] |= mask
+	b.size++
+	return true
+}
+
+// Contains checks if key exists in the set
+func (b *BitSet) Contains(key int) bool {
+	if key < b.lo || key >= b.hi {
+		return false
+	}
+	i, mask := b.bit(key)
+	return b.words[i]&mask != 0
+}
+
+// Remove removes key from the set
+func (b *BitSet) Remove(key int) bool {
+	if key < b.lo || key >= b.hi {
+		return false
+	}
+	i, mask := b.bit(key)
+	if b.words[i]&mask == 0 {
+		return false
+	}
+	b.words[i] &^= mask
+	b.size--
+	return true
+}
+
+// Ascend calls fn for every element in ascending order until fn returns
+// false
+func (b *BitSet) Ascend(fn func(key int) bool) {
+	b.AscendRange(b.lo, b.hi, fn)
+}
+
+// AscendRange calls fn for every element in [from, to) in ascending order
+// until fn returns false
+func (b *BitSet) AscendRange(from, to int, fn func(key int) bool) {
+	if from < b.lo {
+		from = b.lo
+	}
+	if to > b.hi {
+		to = b.hi
+	}
+	if from >= to {
+		return
+	}
+	first, last := (from-b.lo)/64, (to-b.lo-1)/64
+	for i := first; i <= last; i++ {
+		word := b.words[i]
+		if i == first {
+			word &= ^uint64(0) << uint((from-b.lo)%64)
+		}
+		for word != 0 {
+			key := b.lo + i*64 + bits.TrailingZeros64(word)
+			if key >= to {
+				return
+			}
+			if !fn(key) {
+				return
+			}
+			word &= word - 1
+		}
+	}
+}
+
+func (b *BitSet) bit(key int) (int, uint64) {
+	offset := key - b.lo
+	return offset / 64, 1 << uint(offset%64)
+}
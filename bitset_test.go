@@ -0,0 +1,70 @@
+package set
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBitSet(t *testing.T) {
+	const lo, hi = -100, 900
+	b := NewBitSet(lo, hi)
+	rng := rand.New(rand.NewSource(1))
+	want := map[int]bool{}
+	for i := 0; i < 5000; i++ {
+		key := lo - 10 + rng.Intn(hi-lo+20)
+		inRange := key >= lo && key < hi
+		if rng.Intn(2) == 0 {
+			if b.Insert(key) != (inRange && !want[key]) {
+				t.Fatalf("Insert(%d) disagrees", key)
+			}
+			if inRange {
+				want[key] = true
+			}
+		} else {
+			if b.Remove(key) != want[key] {
+				t.Fatalf("Remove(%d) disagrees", key)
+			}
+			delete(want, key)
+		}
+	}
+	if b.Size() != len(want) {
+		t.Fatalf("Size() = %d, want %d", b.Size(), len(want))
+	}
+	for key := lo - 10; key < hi+10; key++ {
+		if b.Contains(key) != want[key] {
+			t.Fatalf("Contains(%d) disagrees", key)
+		}
+	}
+	for _, r := range [][2]int{{lo, hi}, {-200, 2000}, {-37, 500}, {63, 64}, {100, 100}} {
+		prev, count := r[0]-1, 0
+		b.AscendRange(r[0], r[1], func(key int) bool {
+			if key <= prev || key < r[0] || key >= r[1] || !want[key] {
+				t.Fatalf("AscendRange(%d, %d) visited %d after %d", r[0], r[1], key, prev)
+			}
+			prev = key
+			count++
+			return true
+		})
+		expected := 0
+		for key := range want {
+			if key >= r[0] && key < r[1] {
+				expected++
+			}
+		}
+		if count != expected {
+			t.Fatalf("AscendRange(%d, %d) visited %d elements, want %d", r[0], r[1], count, expected)
+		}
+	}
+	visited := 0
+	b.Ascend(func(int) bool {
+		visited++
+		return visited < 3
+	})
+	if visited != 3 {
+		t.Fatalf("Ascend went on after fn returned false: %d visits", visited)
+	}
+	b.Clear()
+	if !b.IsEmpty() || b.Contains(lo) {
+		t.Fatal("Clear left elements")
+	}
+}
@@ -0,0 +1,153 @@
+package set
+
+// EvictionPolicy decides what a BoundedSet does with an insert into a full
+// set
+type EvictionPolicy int
+
+const (
+	// EvictMin drops the smallest element, keeping the largest ones
+	EvictMin EvictionPolicy = iota
+	// EvictMax drops the largest element, keeping the smallest ones
+	EvictMax
+	// RejectNew leaves the set unchanged and rejects the new element
+	RejectNew
+)
+
+// String returns the name of the policy
+func (p EvictionPolicy) String() string {
+	switch p {
+	case EvictMin:
+		return "evict-min"
+	case EvictMax:
+		return "evict-max"
+	case RejectNew:
+		return "reject-new"
+	}
+	return "unknown"
+}
+
+// BoundedSet is a set holding at most a fixed number of elements. With
+// EvictMin it keeps the k largest elements it has seen and with EvictMax
+// the k smallest, which makes it a top-k or bottom-k structure.
+type BoundedSet[T any] struct {
+	set     *Set[T]
+	maxSize int
+	policy  EvictionPolicy
+}
+
+// NewBoundedSet creates a new set with a custom comparator that holds at
+// most maxSize elements, applying policy once it is full
+func NewBoundedSet[T any](compare func(T, T) int, maxSize int, policy EvictionPolicy, opts ...Option[T]) *BoundedSet[T] {
+	return &BoundedSet[T]{
+		set:     NewSetWithOptions(compare, opts...),
+		maxSize: maxSize,
+		policy:  policy,
+	}
+}
+
+// Size returns the number of elements in the set
+func (b *BoundedSet[T]) Size() int {
+	return b.set.Size()
+}
+
+// MaxSize returns the number of elements the set can hold
+func (b *BoundedSet[T]) MaxSize() int {
+	return b.maxSize
+}
+
+// Policy returns the policy applied when the set is full
+func (b *BoundedSet[T]) Policy() EvictionPolicy {
+	return b.policy
+}
+
+// IsEmpty returns true if the set has no elements
+func (b *BoundedSet[T]) IsEmpty() bool {
+	return b.set.IsEmpty()
+}
+
+// IsFull returns true if the set holds its maximum number of elements
+func (b *BoundedSet[T]) IsFull() bool {
+	return b.set.Size() >= b.maxSize
+}
+
+// Clear removes all elements from the set
+func (b *BoundedSet[T]) Clear() {
+	b.set.Clear()
+}
+
+// Insert adds a new element to the set and reports whether it was added.
+// Into a full set, an element is only added if the policy evicts another
+// one for it, which is never the case when it would be evicted itself.
+func (b *BoundedSet[T]) Insert(key T) bool {
+	_, _, inserted := b.offer(key)
+	return inserted
+}
+
+// Contains checks if an element exists in the set
+func (b *BoundedSet[T]) Contains(key T) bool {
+	return b.set.Contains(key)
+}
+
+// Remove removes an element from the set
+func (b *BoundedSet[T]) Remove(key T) bool {
+	return b.set.Remove(key)
+}
+
+// Min returns the smallest element
+func (b *BoundedSet[T]) Min() (T, bool) {
+	return b.set.Min()
+}
+
+// Max returns the largest element
+func (b *BoundedSet[T]) Max() (T, bool) {
+	return b.set.Max()
+}
+
+// Ascend calls fn for every element in ascending order until fn returns
+// false
+func (b *BoundedSet[T]) Ascend(fn func(key T) bool) {
+	b.set.Ascend(fn)
+}
+
+// Descend calls fn for every element in descending order until fn returns
+// false
+func (b *BoundedSet[T]) Descend(fn func(key T) bool) {
+	b.set.Descend(fn)
+}
+
+// ToSlice returns all elements in ascending order
+func (b *BoundedSet[T]) ToSlice() []T {
+	return b.set.ToSlice()
+}
+
+// offer inserts key according to the policy and returns the element
+// evicted to make room for it, if any. A candidate that would be evicted
+// itself is turned away with a single comparison against the boundary
+// element, without touching the tree.
+func (b *BoundedSet[T]) offer(key T) (T, bool, bool) {
+	s := b.set
+	s.checkMutable()
+	key = s.canonical(key)
+	var zero T
+	if s.size < b.maxSize {
+		_, inserted := s.insert(key)
+		return zero, false, inserted
+	}
+	if s.root == nil || b.policy == RejectNew {
+		return zero, false, false
+	}
+	victim := s.minimum(s.root)
+	if b.policy == EvictMax {
+		victim = s.maximum(s.root)
+	}
+	cmp := s.compare(key, victim.key)
+	if cmp == 0 || (cmp < 0) == (b.policy == EvictMin) {
+		return zero, false, false
+	}
+	if _, inserted := s.insert(key); !inserted {
+		return zero, false, false
+	}
+	evicted := victim.key
+	s.removeNode(victim)
+	return evicted, true, true
+}
@@ -0,0 +1,69 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestBoundedSet(t *testing.T) {
+	tests := []struct {
+		policy EvictionPolicy
+		want   []int
+	}{
+		{EvictMin, []int{7, 8, 9}},
+		{EvictMax, []int{1, 2, 3}},
+		{RejectNew, []int{3, 5, 9}},
+	}
+	for _, tt := range tests {
+		b := NewBoundedSet(cmp.Compare[int], 3, tt.policy)
+		for _, key := range []int{5, 3, 9, 1, 8, 2, 7} {
+			b.Insert(key)
+		}
+		if got := b.ToSlice(); !slices.Equal(got, tt.want) {
+			t.Errorf("%v kept %v, want %v", tt.policy, got, tt.want)
+		}
+		if !b.IsFull() || b.set.Validate() != nil {
+			t.Errorf("%v left an invalid or partly filled set", tt.policy)
+		}
+	}
+}
+
+func TestBoundedSetInsert(t *testing.T) {
+	b := NewBoundedSet(cmp.Compare[int], 2, EvictMin)
+	if !b.Insert(1) || !b.Insert(2) || b.Insert(2) {
+		t.Fatal("Insert below the limit misreported")
+	}
+	// A candidate that would be evicted itself is not added
+	if b.Insert(0) || b.Insert(1) {
+		t.Fatalf("a candidate below the kept elements was added: %v", b.ToSlice())
+	}
+	if !b.Insert(3) || b.Contains(1) {
+		t.Fatalf("inserting 3 kept %v", b.ToSlice())
+	}
+	// Removing makes room again
+	b.Remove(3)
+	if !b.Insert(0) || !slices.Equal(b.ToSlice(), []int{0, 2}) {
+		t.Fatalf("insert after a removal kept %v", b.ToSlice())
+	}
+}
+
+func TestBoundedSetRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	b := NewBoundedSet(cmp.Compare[int], 10, EvictMax)
+	seen := map[int]bool{}
+	for i := 0; i < 1000; i++ {
+		key := rng.Intn(500)
+		b.Insert(key)
+		seen[key] = true
+	}
+	var all []int
+	for key := range seen {
+		all = append(all, key)
+	}
+	slices.Sort(all)
+	if got := b.ToSlice(); !slices.Equal(got, all[:10]) {
+		t.Fatalf("kept %v, want the 10 smallest %v", got, all[:10])
+	}
+}
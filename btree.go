@@ -0,0 +1,311 @@
+package set
+
+// LessFunc reports whether a sorts before b, as in github.com/google/btree
+type LessFunc[T any] func(a, b T) bool
+
+// ItemIteratorG is called for the items of a BTreeG range in turn until
+// it returns false, as in github.com/google/btree
+type ItemIteratorG[T any] func(item T) bool
+
+// BTreeG offers the method set of the generic BTreeG of
+// github.com/google/btree on top of a Set, so code written against that
+// package can switch to a red-black tree by changing its constructor
+// calls.
+type BTreeG[T any] struct {
+	set *Set[T]
+}
+
+// NewBTreeG creates a new tree ordered by less. degree is accepted for
+// compatibility and ignored.
+func NewBTreeG[T any](degree int, less LessFunc[T]) *BTreeG[T] {
+	if less == nil {
+		panic(ErrNilComparator)
+	}
+	return &BTreeG[T]{set: NewSet(func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	})}
+}
+
+// Len returns the number of items in the tree
+func (t *BTreeG[T]) Len() int {
+	return t.set.Size()
+}
+
+// Clone returns an independent copy of the tree in O(1) time. Both trees
+// share their nodes, and a write to either copies the nodes on its path.
+func (t *BTreeG[T]) Clone() *BTreeG[T] {
+	clone := t.set.emptyCopy()
+	clone.root, clone.size = t.set.root, t.set.size
+	t.set.share(clone)
+	return &BTreeG[T]{set: clone}
+}
+
+// Clear removes all items. addNodesToFreelist is accepted for
+// compatibility and ignored.
+func (t *BTreeG[T]) Clear(addNodesToFreelist bool) {
+	t.set.Clear()
+}
+
+// ReplaceOrInsert adds item, replacing an equal item if there is one, and
+// returns the replaced item
+func (t *BTreeG[T]) ReplaceOrInsert(item T) (T, bool) {
+	return t.set.Replace(item)
+}
+
+// Delete removes the item equal to item and returns it
+func (t *BTreeG[T]) Delete(item T) (T, bool) {
+	t.set.checkMutable()
+	node := t.set.find(t.set.canonical(item))
+	if node == nil {
+		return keyOf[T](nil)
+	}
+	key := node.key
+	t.set.removeNode(node)
+	return key, true
+}
+
+// DeleteMin removes the smallest item and returns it
+func (t *BTreeG[T]) DeleteMin() (T, bool) {
+	return t.set.PopMin()
+}
+
+// DeleteMax removes the largest item and returns it
+func (t *BTreeG[T]) DeleteMax() (T, bool) {
+	return t.set.PopMax()
+}
+
+// Get returns the item equal to key
+func (t *BTreeG[T]) Get(key T) (T, bool) {
+	return t.set.Find(key)
+}
+
+// Has reports whether an item equal to key is in the tree
+func (t *BTreeG[T]) Has(key T) bool {
+	return t.set.Contains(key)
+}
+
+// Min returns the smallest item
+func (t *BTreeG[T]) Min() (T, bool) {
+	return t.set.Min()
+}
+
+// Max returns the largest item
+func (t *BTreeG[T]) Max() (T, bool) {
+	return t.set.Max()
+}
+
+// Ascend calls iterator for every item in ascending order
+func (t *BTreeG[T]) Ascend(iterator ItemIteratorG[T]) {
+	t.set.Ascend(iterator)
+}
+
+// AscendGreaterOrEqual calls iterator for every item not less than pivot
+// in ascending order
+func (t *BTreeG[T]) AscendGreaterOrEqual(pivot T, iterator ItemIteratorG[T]) {
+	t.ascend(t.set.lowerBound(t.set.canonical(pivot)), nil, iterator)
+}
+
+// AscendLessThan calls iterator for every item less than pivot in
+// ascending order
+func (t *BTreeG[T]) AscendLessThan(pivot T, iterator ItemIteratorG[T]) {
+	if t.set.root == nil {
+		return
+	}
+	t.ascend(t.set.minimum(t.set.root), t.set.lowerBound(t.set.canonical(pivot)), iterator)
+}
+
+// AscendRange calls iterator for every item in [greaterOrEqual, lessThan)
+// in ascending order
+func (t *BTreeG[T]) AscendRange(greaterOrEqual, lessThan T, iterator ItemIteratorG[T]) {
+	t.set.AscendRange(greaterOrEqual, lessThan, iterator)
+}
+
+// Descend calls iterator for every item in descending order
+func (t *BTreeG[T]) Descend(iterator ItemIteratorG[T]) {
+	t.set.Descend(iterator)
+}
+
+// DescendLessOrEqual calls iterator for every item not greater than pivot
+// in descending order
+func (t *BTreeG[T]) DescendLessOrEqual(pivot T, iterator ItemIteratorG[T]) {
+	t.descend(t.set.floor(t.set.canonical(pivot), true), nil, iterator)
+}
+
+// DescendGreaterThan calls iterator for every item greater than pivot in
+// descending order
+func (t *BTreeG[T]) DescendGreaterThan(pivot T, iterator ItemIteratorG[T]) {
+	if t.set.root == nil {
+		return
+	}
+	t.descend(t.set.maximum(t.set.root), t.set.floor(t.set.canonical(pivot), true), iterator)
+}
+
+// DescendRange calls iterator for every item in (greaterThan,
+// lessOrEqual] in descending order
+func (t *BTreeG[T]) DescendRange(lessOrEqual, greaterThan T, iterator ItemIteratorG[T]) {
+	lessOrEqual, greaterThan = t.set.canonical(lessOrEqual), t.set.canonical(greaterThan)
+	if t.set.compare(lessOrEqual, greaterThan) <= 0 {
+		return
+	}
+	t.descend(t.set.floor(lessOrEqual, true), t.set.floor(greaterThan, true), iterator)
+}
+
+// ascend walks from node up to, but excluding, stop
+func (t *BTreeG[T]) ascend(node, stop *Node[T], iterator ItemIteratorG[T]) {
+	for ; node != nil && node != stop; node = t.set.successor(node) {
+		if !iterator(node.key) {
+			return
+		}
+	}
+}
+
+// descend walks from node down to, but excluding, stop
+func (t *BTreeG[T]) descend(node, stop *Node[T], iterator ItemIteratorG[T]) {
+	for ; node != nil && node != stop; node = t.set.predecessor(node) {
+		if !iterator(node.key) {
+			return
+		}
+	}
+}
+
+// Item is the element of a BTree, ordered by its Less method as in
+// github.com/google/btree
+type Item interface {
+	Less(than Item) bool
+}
+
+// ItemIterator is called for the items of a BTree range in turn until it
+// returns false
+type ItemIterator func(item Item) bool
+
+// BTree offers the method set of the non-generic BTree of
+// github.com/google/btree, holding Items. As there, lookups and deletions
+// return a single Item that is nil when there is none.
+type BTree struct {
+	tree *BTreeG[Item]
+}
+
+// NewBTree creates a new tree of Items. degree is accepted for
+// compatibility and ignored.
+func NewBTree(degree int) *BTree {
+	return &BTree{tree: NewBTreeG(degree, func(a, b Item) bool { return a.Less(b) })}
+}
+
+// Len returns the number of items in the tree
+func (t *BTree) Len() int {
+	return t.tree.Len()
+}
+
+// Clone returns an independent copy of the tree in O(1) time
+func (t *BTree) Clone() *BTree {
+	return &BTree{tree: t.tree.Clone()}
+}
+
+// Clear removes all items. addNodesToFreelist is accepted for
+// compatibility and ignored.
+func (t *BTree) Clear(addNodesToFreelist bool) {
+	t.tree.Clear(addNodesToFreelist)
+}
+
+// ReplaceOrInsert adds item, replacing an equal item if there is one, and
+// returns the replaced item or nil
+func (t *BTree) ReplaceOrInsert(item Item) Item {
+	old, _ := t.tree.ReplaceOrInsert(item)
+	return old
+}
+
+// Delete removes the item equal to item and returns it, or nil if there
+// is none
+func (t *BTree) Delete(item Item) Item {
+	old, _ := t.tree.Delete(item)
+	return old
+}
+
+// DeleteMin removes the smallest item and returns it, or nil if the tree
+// is empty
+func (t *BTree) DeleteMin() Item {
+	item, _ := t.tree.DeleteMin()
+	return item
+}
+
+// DeleteMax removes the largest item and returns it, or nil if the tree
+// is empty
+func (t *BTree) DeleteMax() Item {
+	item, _ := t.tree.DeleteMax()
+	return item
+}
+
+// Get returns the item equal to key, or nil if there is none
+func (t *BTree) Get(key Item) Item {
+	item, _ := t.tree.Get(key)
+	return item
+}
+
+// Has reports whether an item equal to key is in the tree
+func (t *BTree) Has(key Item) bool {
+	return t.tree.Has(key)
+}
+
+// Min returns the smallest item, or nil if the tree is empty
+func (t *BTree) Min() Item {
+	item, _ := t.tree.Min()
+	return item
+}
+
+// Max returns the largest item, or nil if the tree is empty
+func (t *BTree) Max() Item {
+	item, _ := t.tree.Max()
+	return item
+}
+
+// Ascend calls iterator for every item in ascending order
+func (t *BTree) Ascend(iterator ItemIterator) {
+	t.tree.Ascend(ItemIteratorG[Item](iterator))
+}
+
+// AscendGreaterOrEqual calls iterator for every item not less than pivot
+// in ascending order
+func (t *BTree) AscendGreaterOrEqual(pivot Item, iterator ItemIterator) {
+	t.tree.AscendGreaterOrEqual(pivot, ItemIteratorG[Item](iterator))
+}
+
+// AscendLessThan calls iterator for every item less than pivot in
+// ascending order
+func (t *BTree) AscendLessThan(pivot Item, iterator ItemIterator) {
+	t.tree.AscendLessThan(pivot, ItemIteratorG[Item](iterator))
+}
+
+// AscendRange calls iterator for every item in [greaterOrEqual, lessThan)
+// in ascending order
+func (t *BTree) AscendRange(greaterOrEqual, lessThan Item, iterator ItemIterator) {
+	t.tree.AscendRange(greaterOrEqual, lessThan, ItemIteratorG[Item](iterator))
+}
+
+// Descend calls iterator for every item in descending order
+func (t *BTree) Descend(iterator ItemIterator) {
+	t.tree.Descend(ItemIteratorG[Item](iterator))
+}
+
+// DescendLessOrEqual calls iterator for every item not greater than pivot
+// in descending order
+func (t *BTree) DescendLessOrEqual(pivot Item, iterator ItemIterator) {
+	t.tree.DescendLessOrEqual(pivot, ItemIteratorG[Item](iterator))
+}
+
+// DescendGreaterThan calls iterator for every item greater than pivot in
+// descending order
+func (t *BTree) DescendGreaterThan(pivot Item, iterator ItemIterator) {
+	t.tree.DescendGreaterThan(pivot, ItemIteratorG[Item](iterator))
+}
+
+// DescendRange calls iterator for every item in (greaterThan,
+// lessOrEqual] in descending order
+func (t *BTree) DescendRange(lessOrEqual, greaterThan Item, iterator ItemIterator) {
+	t.tree.DescendRange(lessOrEqual, greaterThan, ItemIteratorG[Item](iterator))
+}
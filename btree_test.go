@@ -0,0 +1,194 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+// googleBTreeG is the method set of btree.BTreeG in github.com/google/btree
+type googleBTreeG[T any] interface {
+	Len() int
+	ReplaceOrInsert(item T) (T, bool)
+	Delete(item T) (T, bool)
+	DeleteMin() (T, bool)
+	DeleteMax() (T, bool)
+	Get(key T) (T, bool)
+	Has(key T) bool
+	Min() (T, bool)
+	Max() (T, bool)
+	Clear(addNodesToFreelist bool)
+	Ascend(iterator ItemIteratorG[T])
+	AscendGreaterOrEqual(pivot T, iterator ItemIteratorG[T])
+	AscendLessThan(pivot T, iterator ItemIteratorG[T])
+	AscendRange(greaterOrEqual, lessThan T, iterator ItemIteratorG[T])
+	Descend(iterator ItemIteratorG[T])
+	DescendLessOrEqual(pivot T, iterator ItemIteratorG[T])
+	DescendGreaterThan(pivot T, iterator ItemIteratorG[T])
+	DescendRange(lessOrEqual, greaterThan T, iterator ItemIteratorG[T])
+}
+
+// googleBTree is the method set of btree.BTree in github.com/google/btree
+type googleBTree interface {
+	Len() int
+	Clone() *BTree
+	ReplaceOrInsert(item Item) Item
+	Delete(item Item) Item
+	DeleteMin() Item
+	DeleteMax() Item
+	Get(key Item) Item
+	Has(key Item) bool
+	Min() Item
+	Max() Item
+	Clear(addNodesToFreelist bool)
+	Ascend(iterator ItemIterator)
+	AscendGreaterOrEqual(pivot Item, iterator ItemIterator)
+	AscendLessThan(pivot Item, iterator ItemIterator)
+	AscendRange(greaterOrEqual, lessThan Item, iterator ItemIterator)
+	Descend(iterator ItemIterator)
+	DescendLessOrEqual(pivot Item, iterator ItemIterator)
+	DescendGreaterThan(pivot Item, iterator ItemIterator)
+	DescendRange(lessOrEqual, greaterThan Item, iterator ItemIterator)
+}
+
+var (
+	_ googleBTreeG[int] = (*BTreeG[int])(nil)
+	_ googleBTree       = (*BTree)(nil)
+)
+
+// intItem is an Item ordered by its value, like btree.Int
+type intItem int
+
+func (a intItem) Less(b Item) bool {
+	return a < b.(intItem)
+}
+
+func collectG(walk func(ItemIteratorG[int])) []int {
+	var got []int
+	walk(func(item int) bool {
+		got = append(got, item)
+		return true
+	})
+	return got
+}
+
+func TestBTreeG(t *testing.T) {
+	tr := NewBTreeG(32, func(a, b int) bool { return a < b })
+	for i := 10; i > 0; i-- {
+		if _, replaced := tr.ReplaceOrInsert(i); replaced {
+			t.Fatalf("ReplaceOrInsert(%d) replaced into an empty slot", i)
+		}
+	}
+	if old, replaced := tr.ReplaceOrInsert(5); !replaced || old != 5 {
+		t.Fatalf("ReplaceOrInsert(5) = %d, %v", old, replaced)
+	}
+	cases := []struct {
+		name string
+		walk func(ItemIteratorG[int])
+		want []int
+	}{
+		{"Ascend", tr.Ascend, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{"AscendGreaterOrEqual", func(f ItemIteratorG[int]) { tr.AscendGreaterOrEqual(8, f) }, []int{8, 9, 10}},
+		{"AscendLessThan", func(f ItemIteratorG[int]) { tr.AscendLessThan(3, f) }, []int{1, 2}},
+		{"AscendRange", func(f ItemIteratorG[int]) { tr.AscendRange(4, 7, f) }, []int{4, 5, 6}},
+		{"Descend", tr.Descend, []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+		{"DescendLessOrEqual", func(f ItemIteratorG[int]) { tr.DescendLessOrEqual(3, f) }, []int{3, 2, 1}},
+		{"DescendGreaterThan", func(f ItemIteratorG[int]) { tr.DescendGreaterThan(8, f) }, []int{10, 9}},
+		{"DescendRange", func(f ItemIteratorG[int]) { tr.DescendRange(7, 4, f) }, []int{7, 6, 5}},
+	}
+	for _, c := range cases {
+		if got := collectG(c.walk); !slices.Equal(got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, got, c.want)
+		}
+	}
+	if min, ok := tr.DeleteMin(); !ok || min != 1 {
+		t.Fatalf("DeleteMin() = %d, %v", min, ok)
+	}
+	if max, ok := tr.DeleteMax(); !ok || max != 10 {
+		t.Fatalf("DeleteMax() = %d, %v", max, ok)
+	}
+	if _, ok := tr.Delete(42); ok {
+		t.Fatal("Delete of a missing item succeeded")
+	}
+	if tr.Len() != 8 || tr.Has(1) || !tr.Has(2) {
+		t.Fatalf("tree holds %v", collectG(tr.Ascend))
+	}
+}
+
+func TestBTree(t *testing.T) {
+	tr := NewBTree(2)
+	if tr.Min() != nil || tr.DeleteMax() != nil || tr.Get(intItem(1)) != nil {
+		t.Fatal("an empty tree returned an item")
+	}
+	for i := 0; i < 10; i++ {
+		if old := tr.ReplaceOrInsert(intItem(i)); old != nil {
+			t.Fatalf("ReplaceOrInsert(%d) = %v", i, old)
+		}
+	}
+	if old := tr.ReplaceOrInsert(intItem(3)); old != intItem(3) {
+		t.Fatalf("ReplaceOrInsert of a present item = %v", old)
+	}
+	if got := tr.Delete(intItem(4)); got != intItem(4) {
+		t.Fatalf("Delete(4) = %v", got)
+	}
+	if got := tr.Delete(intItem(4)); got != nil {
+		t.Fatalf("second Delete(4) = %v", got)
+	}
+	var got []Item
+	tr.AscendRange(intItem(2), intItem(7), func(item Item) bool {
+		got = append(got, item)
+		return true
+	})
+	if want := []Item{intItem(2), intItem(3), intItem(5), intItem(6)}; !slices.Equal(got, want) {
+		t.Fatalf("AscendRange = %v, want %v", got, want)
+	}
+	if tr.Min() != intItem(0) || tr.Max() != intItem(9) || tr.Len() != 9 {
+		t.Fatalf("Min %v, Max %v, Len %d", tr.Min(), tr.Max(), tr.Len())
+	}
+}
+
+func TestBTreeClone(t *testing.T) {
+	tr := NewBTreeG(2, func(a, b int) bool { return a < b })
+	for i := 0; i < 1000; i++ {
+		tr.ReplaceOrInsert(i)
+	}
+	clones := []*BTreeG[int]{tr, tr.Clone(), tr.Clone()}
+	clones = append(clones, clones[1].Clone())
+	for i, c := range clones {
+		for key := i; key < 1000; key += len(clones) {
+			c.Delete(key)
+		}
+		c.ReplaceOrInsert(1000 + i)
+	}
+	for i, c := range clones {
+		want := []int{}
+		for key := 0; key < 1000; key++ {
+			if key%len(clones) != i {
+				want = append(want, key)
+			}
+		}
+		want = append(want, 1000+i)
+		if got := collectG(c.Ascend); !slices.Equal(got, want) {
+			t.Fatalf("clone %d holds %d items, want %d", i, len(got), len(want))
+		}
+		if got := collectG(c.Descend); len(got) != len(want) || got[0] != 1000+i {
+			t.Fatalf("clone %d descends over %d items", i, len(got))
+		}
+	}
+	if err := tr.set.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBTreeGNormalizes(t *testing.T) {
+	// Lookups and deletions go through the same key handling as inserts
+	tr := NewBTreeG(2, func(a, b int) bool { return cmp.Less(a, b) })
+	tr.set.normalize = func(key int) int { return key - key%10 }
+	tr.ReplaceOrInsert(23)
+	if !tr.Has(27) {
+		t.Fatal("Has ignores normalization")
+	}
+	if _, ok := tr.Delete(25); !ok || tr.Len() != 0 {
+		t.Fatal("Delete ignores normalization")
+	}
+}
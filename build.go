@@ -0,0 +1,216 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// ErrNotSorted is returned when bulk-building from input that is not in
+// ascending order
+var ErrNotSorted = errors.New("set: input is not sorted")
+
+// ErrDuplicate is returned when bulk-building from input with equal
+// adjacent elements
+var ErrDuplicate = errors.New("set: input contains duplicates")
+
+// NewSetFromSlice creates a new set holding the elements of items. The
+// items are copied, sorted and bulk-built into a balanced tree; of several
+// equal items only the first is kept. The new set has no size limit.
+func NewSetFromSlice[T any](compare func(T, T) int, items []T) *Set[T] {
+	s := NewSet(compare)
+	s.buildSorted(sortUnique(append([]T(nil), items...), compare))
+	return s
+}
+
+// BuildFromSorted replaces the contents of s with items, which must be in
+// strictly ascending order, building a balanced tree in linear time. It
+// returns an error wrapping ErrNotSorted or ErrDuplicate, leaving s
+// untouched, if the order is violated.
+func (s *Set[T]) BuildFromSorted(items []T) error {
+	s.checkMutable()
+	keys := make([]T, len(items))
+	for i, item := range items {
+		keys[i] = s.canonical(item)
+		if i == 0 {
+			continue
+		}
+		switch cmp := s.compare(keys[i-1], keys[i]); {
+		case cmp == 0:
+			return fmt.Errorf("%w: %v at index %d", ErrDuplicate, items[i], i)
+		case cmp > 0:
+			return fmt.Errorf("%w: %v at index %d", ErrNotSorted, items[i], i)
+		}
+	}
+	if s.maxSize > 0 && len(keys) > s.maxSize {
+		return fmt.Errorf("%w: %d elements", ErrFull, len(keys))
+	}
+	for i, key := range keys {
+		keys[i] = s.stored(key)
+	}
+	root := s.root
+	s.buildSorted(keys)
+	s.cleared(root)
+	for _, key := range keys {
+		s.changed(ChangeInsert, key)
+	}
+	return nil
+}
+
+// InsertBatch adds the elements of items and returns how many were not
+// yet present. Rather than rebalancing after every insert, the items are
+// sorted, bulk-built into a balanced tree and merged in with Merge, which
+// takes O(m log m + m log(n/m + 1)) time for m items.
+func (s *Set[T]) InsertBatch(items []T) int {
+	s.checkMutable()
+	keys := make([]T, len(items))
+	for i, item := range items {
+		keys[i] = s.canonical(item)
+	}
+	keys = sortUnique(keys, s.compare)
+	for i, key := range keys {
+		keys[i] = s.stored(key)
+	}
+	old := s.size
+	s.absorb(s.buildTree(keys))
+	return s.size - old
+}
+
+// ToSlice returns the elements in ascending order
+func (s *Set[T]) ToSlice() []T {
+	return s.keys()
+}
+
+// Reorder returns a new set holding the elements of s ordered by compare.
+// Elements are extracted, sorted and bulk-built into the new tree; when
+// several elements are equal under compare only the first one in the old
+// order is kept. The new set has no size limit.
+func (s *Set[T]) Reorder(compare func(T, T) int) *Set[T] {
+	result := s.emptyCopy()
+	result.compare = compare
+	result.buildSorted(sortUnique(s.keys(), compare))
+	return result
+}
+
+// ImportFrom adds the elements of other to s even when other uses a
+// different comparator. The elements are re-sorted under the comparator of
+// s and merged with the existing ones into a freshly built tree; existing
+// elements win over equal imported ones. If the result would take a set
+// created WithMaxSize past its limit, it returns an error wrapping ErrFull
+// and leaves s untouched.
+func (s *Set[T]) ImportFrom(other *Set[T]) error {
+	s.checkMutable()
+	imported := other.keys()
+	for i, key := range imported {
+		imported[i] = s.canonical(key)
+	}
+	imported = sortUnique(imported, s.compare)
+	existing := s.keys()
+	merged := make([]T, 0, len(existing)+len(imported))
+	var added []T
+	i, j := 0, 0
+	for i < len(existing) || j < len(imported) {
+		cmp := -1
+		switch {
+		case i == len(existing):
+			cmp = 1
+		case j < len(imported):
+			cmp = s.compare(existing[i], imported[j])
+		}
+		switch {
+		case cmp < 0:
+			merged = append(merged, existing[i])
+			i++
+		case cmp > 0:
+			key := s.stored(imported[j])
+			merged = append(merged, key)
+			added = append(added, key)
+			j++
+		default:
+			merged = append(merged, existing[i])
+			i++
+			j++
+		}
+	}
+	if s.maxSize > 0 && len(merged) > s.maxSize {
+		return fmt.Errorf("%w: %d elements", ErrFull, len(merged))
+	}
+	s.buildSorted(merged)
+	for _, key := range added {
+		s.changed(ChangeInsert, key)
+	}
+	return nil
+}
+
+// keys returns the elements of s in order
+func (s *Set[T]) keys() []T {
+	keys := make([]T, 0, s.size)
+	if s.root == nil {
+		return keys
+	}
+	for node := s.minimum(s.root); node != nil; node = s.successor(node) {
+		keys = append(keys, node.key)
+	}
+	return keys
+}
+
+// sortUnique sorts keys by compare and drops all but the first of every run
+// of equal keys
+func sortUnique[T any](keys []T, compare func(T, T) int) []T {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compare(keys[i], keys[j]) < 0
+	})
+	unique := keys[:0]
+	for _, key := range keys {
+		if len(unique) == 0 || compare(unique[len(unique)-1], key) != 0 {
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
+
+// buildSorted replaces the contents of s with keys, which must be strictly
+// ascending under the comparator, building a balanced tree in linear time
+func (s *Set[T]) buildSorted(keys []T) {
+	old := s.size
+	s.root = s.buildTree(keys)
+	s.size = len(keys)
+	s.mods++
+	s.resized(old)
+}
+
+// buildTree returns the root of a balanced tree holding keys, which must
+// be strictly ascending, without attaching it to s
+func (s *Set[T]) buildTree(keys []T) *Node[T] {
+	// The midpoint build puts every leaf on the last two levels. Coloring
+	// the last level red when it is incomplete gives every path the same
+	// number of black nodes.
+	redDepth := 0
+	if n := len(keys); n&(n+1) != 0 {
+		redDepth = bits.Len(uint(n))
+	}
+	return s.buildBalanced(keys, nil, 1, redDepth)
+}
+
+// buildBalanced builds the subtree of keys below parent, taking its nodes
+// from the node allocator of s and computing their augmented data bottom
+// up
+func (s *Set[T]) buildBalanced(keys []T, parent *Node[T], depth, redDepth int) *Node[T] {
+	if len(keys) == 0 {
+		return nil
+	}
+	mid := len(keys) / 2
+	color := Black
+	if depth == redDepth {
+		color = Red
+	}
+	node := s.newNode(keys[mid], color, parent)
+	node.left = s.buildBalanced(keys[:mid], node, depth+1, redDepth)
+	node.right = s.buildBalanced(keys[mid+1:], node, depth+1, redDepth)
+	node.size = len(keys)
+	if s.augment != nil {
+		s.augment(node)
+	}
+	return node
+}
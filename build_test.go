@@ -0,0 +1,128 @@
+package set
+
+import (
+	"cmp"
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestNewSetFromSlice(t *testing.T) {
+	s := NewSetFromSlice(cmp.Compare[int], []int{5, 3, 5, 1, 3})
+	checkSet(t, s, map[int]bool{1: true, 3: true, 5: true})
+}
+
+func TestReorder(t *testing.T) {
+	s := intSet(1, 2, 3, 12, 13)
+	byLastDigit := func(a, b int) int { return cmp.Compare(a%10, b%10) }
+	r := s.Reorder(byLastDigit)
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	// 12 and 13 collide with 2 and 3; the first in the old order wins
+	if got, want := r.ToSlice(), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Fatalf("Reorder = %v, want %v", got, want)
+	}
+	if s.Size() != 5 {
+		t.Fatal("Reorder changed the source set")
+	}
+}
+
+func TestImportFrom(t *testing.T) {
+	s := intSet(1, 5)
+	other := NewSet(func(a, b int) int { return cmp.Compare(b, a) })
+	for _, key := range []int{9, 5, 2} {
+		other.Insert(key)
+	}
+	if err := s.ImportFrom(other); err != nil {
+		t.Fatal(err)
+	}
+	checkSet(t, s, map[int]bool{1: true, 2: true, 5: true, 9: true})
+}
+
+func TestImportFromFull(t *testing.T) {
+	s := NewSetWithOptions(cmp.Compare[int], WithMaxSize[int](3))
+	s.Insert(1)
+	s.Insert(2)
+	if err := s.ImportFrom(intSet(2, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ImportFrom(intSet(3, 4)); !errors.Is(err, ErrFull) {
+		t.Fatalf("ImportFrom past the limit: %v", err)
+	}
+	checkSet(t, s, map[int]bool{1: true, 2: true, 3: true})
+}
+
+func TestToSlice(t *testing.T) {
+	if got := intSet().ToSlice(); got == nil || len(got) != 0 {
+		t.Fatalf("ToSlice of an empty set = %#v", got)
+	}
+	s := intSet(3, 1, 2)
+	got := s.ToSlice()
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("ToSlice() = %v", got)
+	}
+	got[0] = 100
+	if !s.Contains(1) {
+		t.Fatal("ToSlice shares storage with the set")
+	}
+	if back := NewSetFromSlice(cmp.Compare[int], s.ToSlice()); !back.Equal(s) {
+		t.Fatal("round trip through a slice lost elements")
+	}
+}
+
+func TestBuildFromSorted(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 100, 1023, 1024} {
+		items := make([]int, n)
+		for i := range items {
+			items[i] = 2 * i
+		}
+		s := intSet(-1, 5000)
+		if err := s.BuildFromSorted(items); err != nil {
+			t.Fatal(err)
+		}
+		want := map[int]bool{}
+		for _, key := range items {
+			want[key] = true
+		}
+		checkSet(t, s, want)
+	}
+}
+
+func TestBuildFromSortedErrors(t *testing.T) {
+	s := intSet(42)
+	if err := s.BuildFromSorted([]int{1, 3, 2}); !errors.Is(err, ErrNotSorted) {
+		t.Fatalf("unsorted input: %v", err)
+	}
+	if err := s.BuildFromSorted([]int{1, 2, 2}); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("duplicate input: %v", err)
+	}
+	full := NewSetWithOptions(cmp.Compare[int], WithMaxSize[int](2))
+	if err := full.BuildFromSorted([]int{1, 2, 3}); !errors.Is(err, ErrFull) {
+		t.Fatalf("input past the limit: %v", err)
+	}
+	checkSet(t, s, map[int]bool{42: true})
+	checkSet(t, full, map[int]bool{})
+}
+
+func TestInsertBatch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := intSet()
+	want := map[int]bool{}
+	for round := 0; round < 20; round++ {
+		batch := make([]int, rng.Intn(200))
+		added := 0
+		for i := range batch {
+			batch[i] = rng.Intn(2000)
+			if !want[batch[i]] {
+				want[batch[i]] = true
+				added++
+			}
+		}
+		if n := s.InsertBatch(batch); n != added {
+			t.Fatalf("InsertBatch added %d, want %d", n, added)
+		}
+		checkSet(t, s, want)
+	}
+}
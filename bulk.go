@@ -0,0 +1,95 @@
+package set
+
+// ContainsAll returns true if every one of keys is in the set. For a set
+// of keys, IsSupersetOf does the same with a single merged walk.
+func (s *Set[T]) ContainsAll(keys ...T) bool {
+	for _, key := range keys {
+		if !s.Contains(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny returns true if at least one of keys is in the set. For a
+// set of keys, IsDisjointFrom answers the opposite question.
+func (s *Set[T]) ContainsAny(keys ...T) bool {
+	for _, key := range keys {
+		if s.Contains(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddAll adds keys to the set and returns how many were not yet present.
+// It is InsertBatch for a variadic list; Merge adds the elements of
+// another set.
+func (s *Set[T]) AddAll(keys ...T) int {
+	return s.InsertBatch(keys)
+}
+
+// RemoveAll removes keys from the set and returns how many were present.
+// RemoveSet removes the elements of another set.
+func (s *Set[T]) RemoveAll(keys ...T) int {
+	removed := 0
+	for _, key := range keys {
+		if s.Remove(key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// RetainAll removes every element that is not one of keys and returns how
+// many were removed. The keys are sorted once and matched against the set
+// in a single in-order pass.
+func (s *Set[T]) RetainAll(keys ...T) int {
+	s.checkMutable()
+	keep := make([]T, len(keys))
+	for i, key := range keys {
+		keep[i] = s.canonical(key)
+	}
+	keep = sortUnique(keep, s.compare)
+	return s.RemoveIf(func(key T) bool {
+		for len(keep) > 0 && s.compare(keep[0], key) < 0 {
+			keep = keep[1:]
+		}
+		return len(keep) == 0 || s.compare(keep[0], key) != 0
+	})
+}
+
+// RemoveSet removes every element that is also in other and returns how
+// many were removed. other must be ordered like s, as for IsSubsetOf:
+// both sets are walked in order together, in O(n+m) time plus the cost of
+// the removals.
+func (s *Set[T]) RemoveSet(other *Set[T]) int {
+	if other == s {
+		removed := s.size
+		s.Clear()
+		return removed
+	}
+	b := other.first()
+	return s.RemoveIf(func(key T) bool {
+		for b != nil && s.compare(b.key, key) < 0 {
+			b = other.successor(b)
+		}
+		return b != nil && s.compare(b.key, key) == 0
+	})
+}
+
+// RetainSet removes every element that is not in other and returns how
+// many were removed, walking both sets in order together as RemoveSet
+// does
+func (s *Set[T]) RetainSet(other *Set[T]) int {
+	if other == s {
+		return 0
+	}
+	b := other.first()
+	return s.RemoveIf(func(key T) bool {
+		for b != nil && s.compare(b.key, key) < 0 {
+			b = other.successor(b)
+		}
+		return b == nil || s.compare(b.key, key) != 0
+	})
+}
@@ -0,0 +1,49 @@
+package set
+
+import "testing"
+
+func TestBulkPredicates(t *testing.T) {
+	s := intSet(1, 2, 3)
+	if !s.ContainsAll(1, 3) || s.ContainsAll(1, 4) || !s.ContainsAll() {
+		t.Fatal("ContainsAll is wrong")
+	}
+	if !s.ContainsAny(4, 2) || s.ContainsAny(4, 5) || s.ContainsAny() {
+		t.Fatal("ContainsAny is wrong")
+	}
+}
+
+func TestBulkMutators(t *testing.T) {
+	s := intSet()
+	if n := s.AddAll(5, 1, 5, 3, 7, 9); n != 5 {
+		t.Fatalf("AddAll added %d, want 5", n)
+	}
+	if n := s.RemoveAll(1, 2, 9, 9); n != 2 {
+		t.Fatalf("RemoveAll removed %d, want 2", n)
+	}
+	checkSet(t, s, map[int]bool{3: true, 5: true, 7: true})
+	if n := s.RetainAll(7, 3, 100, 3); n != 1 {
+		t.Fatalf("RetainAll removed %d, want 1", n)
+	}
+	checkSet(t, s, map[int]bool{3: true, 7: true})
+	if n := s.RetainAll(); n != 2 || !s.IsEmpty() {
+		t.Fatalf("RetainAll of nothing removed %d", n)
+	}
+}
+
+func TestRemoveRetainSet(t *testing.T) {
+	s := intSet(1, 2, 3, 4, 5, 6)
+	if n := s.RemoveSet(intSet(0, 2, 4, 8)); n != 2 {
+		t.Fatalf("RemoveSet removed %d, want 2", n)
+	}
+	checkSet(t, s, map[int]bool{1: true, 3: true, 5: true, 6: true})
+	if n := s.RetainSet(intSet(3, 4, 6, 7)); n != 2 {
+		t.Fatalf("RetainSet removed %d, want 2", n)
+	}
+	checkSet(t, s, map[int]bool{3: true, 6: true})
+	if n := s.RetainSet(s); n != 0 || s.Size() != 2 {
+		t.Fatal("RetainSet of itself removed elements")
+	}
+	if n := s.RemoveSet(s); n != 2 || !s.IsEmpty() {
+		t.Fatal("RemoveSet of itself left elements")
+	}
+}
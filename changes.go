@@ -0,0 +1,184 @@
+package set
+
+import (
+	"errors"
+	"sync"
+)
+
+// ChangeKind identifies the kind of a change to a set
+type ChangeKind int
+
+const (
+	ChangeInsert ChangeKind = iota
+	ChangeRemove
+	ChangeClear
+)
+
+// Change describes a single successful mutation of a set. Seq numbers are
+// assigned in mutation order starting at 1; Key is unset for ChangeClear.
+type Change[T any] struct {
+	Seq  uint64
+	Kind ChangeKind
+	Key  T
+}
+
+// ErrHistoryTruncated is returned when a subscription asks to catch up
+// from a change that is no longer retained
+var ErrHistoryTruncated = errors.New("set: change history no longer covers the requested sequence")
+
+// WithChangeHistory keeps the last n changes so that subscribers can catch
+// up with SubscribeFrom
+func WithChangeHistory[T any](n int) Option[T] {
+	return func(s *Set[T]) {
+		s.feed().keep = n
+	}
+}
+
+// WithOnInsert registers fn to be called with every element added to the
+// set, right after the insert took effect. Observers run synchronously on
+// the mutating goroutine, before subscribers are notified.
+func WithOnInsert[T any](fn func(key T)) Option[T] {
+	return func(s *Set[T]) {
+		s.onInsert = append(s.onInsert, fn)
+	}
+}
+
+// WithOnRemove registers fn to be called with every element removed from
+// the set, including those dropped by Clear and by operations that replace
+// or move out the whole contents
+func WithOnRemove[T any](fn func(key T)) Option[T] {
+	return func(s *Set[T]) {
+		s.onRemove = append(s.onRemove, fn)
+	}
+}
+
+// changeFeed numbers the changes of a set and fans them out to subscribers
+type changeFeed[T any] struct {
+	mu      sync.Mutex
+	seq     uint64
+	keep    int
+	history []Change[T]
+	subs    []*subscriber[T]
+}
+
+type subscriber[T any] struct {
+	ch   chan Change[T]
+	done chan struct{}
+	once sync.Once
+}
+
+// Subscribe returns a channel receiving every subsequent change in order,
+// and a function cancelling the subscription and closing the channel.
+// Delivery is lossless, so a mutation blocks while a subscriber's buffer
+// is full; subscribers must keep draining or cancel.
+func (s *Set[T]) Subscribe(buffer int) (<-chan Change[T], func()) {
+	ch, cancel, _ := s.subscribe(0, false, buffer)
+	return ch, cancel
+}
+
+// SubscribeFrom is like Subscribe but first delivers the retained changes
+// after seq, the last sequence number the caller has seen. The channel
+// buffer is enlarged to hold the catch-up changes. It fails with
+// ErrHistoryTruncated if some of those changes are no longer retained.
+func (s *Set[T]) SubscribeFrom(seq uint64, buffer int) (<-chan Change[T], func(), error) {
+	return s.subscribe(seq, true, buffer)
+}
+
+func (s *Set[T]) subscribe(seq uint64, catchUp bool, buffer int) (<-chan Change[T], func(), error) {
+	f := s.feed()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var replay []Change[T]
+	if catchUp && seq < f.seq {
+		if len(f.history) == 0 || f.history[0].Seq > seq+1 {
+			return nil, nil, ErrHistoryTruncated
+		}
+		replay = f.history[seq+1-f.history[0].Seq:]
+	}
+	sub := &subscriber[T]{
+		ch:   make(chan Change[T], buffer+len(replay)),
+		done: make(chan struct{}),
+	}
+	for _, c := range replay {
+		sub.ch <- c
+	}
+	f.subs = append(f.subs, sub)
+	cancel := func() {
+		sub.once.Do(func() {
+			close(sub.done)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			for i, other := range f.subs {
+				if other == sub {
+					f.subs = append(f.subs[:i], f.subs[i+1:]...)
+					break
+				}
+			}
+			close(sub.ch)
+		})
+	}
+	return sub.ch, cancel, nil
+}
+
+func (s *Set[T]) feed() *changeFeed[T] {
+	if s.changes == nil {
+		s.changes = &changeFeed[T]{}
+	}
+	return s.changes
+}
+
+// observed reports whether individual changes are reported to anyone, so
+// that bulk operations know whether to enumerate the elements they touch
+func (s *Set[T]) observed() bool {
+	return s.changes != nil || len(s.onInsert) > 0 || len(s.onRemove) > 0
+}
+
+// cleared reports the removal of all elements, held by the trees rooted at
+// roots
+func (s *Set[T]) cleared(roots ...*Node[T]) {
+	for _, root := range roots {
+		for _, fn := range s.onRemove {
+			ascend(root, func(key T) bool {
+				fn(key)
+				return true
+			})
+		}
+	}
+	var zero T
+	s.changed(ChangeClear, zero)
+}
+
+// changed records a successful mutation, calls the observers and delivers
+// it to subscribers
+func (s *Set[T]) changed(kind ChangeKind, key T) {
+	switch kind {
+	case ChangeInsert:
+		for _, fn := range s.onInsert {
+			fn(key)
+		}
+	case ChangeRemove:
+		for _, fn := range s.onRemove {
+			fn(key)
+		}
+	}
+	f := s.changes
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	c := Change[T]{Seq: f.seq, Kind: kind, Key: key}
+	if f.keep > 0 {
+		f.history = append(f.history, c)
+		if len(f.history) > f.keep {
+			f.history = f.history[len(f.history)-f.keep:]
+		}
+	}
+	for _, sub := range f.subs {
+		select {
+		case sub.ch <- c:
+		case <-sub.done:
+		}
+	}
+}
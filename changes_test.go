@@ -0,0 +1,104 @@
+package set
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSubscribe(t *testing.T) {
+	s := NewSet(cmp.Compare[int])
+	ch, cancel := s.Subscribe(10)
+	s.Insert(1)
+	s.Insert(1)
+	s.Insert(2)
+	s.Remove(1)
+	s.Remove(7)
+	s.Clear()
+	want := []Change[int]{
+		{Seq: 1, Kind: ChangeInsert, Key: 1},
+		{Seq: 2, Kind: ChangeInsert, Key: 2},
+		{Seq: 3, Kind: ChangeRemove, Key: 1},
+		{Seq: 4, Kind: ChangeClear},
+	}
+	for _, w := range want {
+		if c := <-ch; c != w {
+			t.Fatalf("received %+v, want %+v", c, w)
+		}
+	}
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Fatal("the channel is open after cancel")
+	}
+	cancel()
+	// Nothing blocks on a cancelled subscriber
+	s.Insert(3)
+}
+
+func TestSubscribeFrom(t *testing.T) {
+	s := NewSetWithOptions(cmp.Compare[int], WithChangeHistory[int](3))
+	for i := 1; i <= 5; i++ {
+		s.Insert(i)
+	}
+	ch, cancel, err := s.SubscribeFrom(3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	for _, seq := range []uint64{4, 5} {
+		if c := <-ch; c.Seq != seq || c.Key != int(seq) {
+			t.Fatalf("caught up with %+v, want sequence %d", c, seq)
+		}
+	}
+	go s.Insert(6)
+	if c := <-ch; c.Seq != 6 {
+		t.Fatalf("received %+v after catching up", c)
+	}
+	if _, _, err := s.SubscribeFrom(1, 0); !errors.Is(err, ErrHistoryTruncated) {
+		t.Fatalf("SubscribeFrom past the history: %v", err)
+	}
+	if _, cancel, err := s.SubscribeFrom(6, 0); err != nil {
+		t.Fatalf("SubscribeFrom the latest change: %v", err)
+	} else {
+		cancel()
+	}
+}
+
+func TestObservers(t *testing.T) {
+	var inserted, removed []int
+	opts := []Option[int]{
+		WithOnInsert(func(key int) { inserted = append(inserted, key) }),
+		WithOnRemove(func(key int) { removed = append(removed, key) }),
+	}
+	check := func(what string, ins, rem []int) {
+		t.Helper()
+		if !slices.Equal(inserted, ins) || !slices.Equal(removed, rem) {
+			t.Fatalf("after %s observed inserts %v and removals %v, want %v and %v", what, inserted, removed, ins, rem)
+		}
+		inserted, removed = nil, nil
+	}
+	s := NewSetWithOptions(cmp.Compare[int], opts...)
+	s.Insert(1)
+	s.Insert(1)
+	s.Insert(3)
+	s.Remove(1)
+	s.Remove(7)
+	check("inserts and removals", []int{1, 3}, []int{1})
+
+	// Only the elements new to s are reported by a merge
+	s.Merge(intSet(2, 3, 4))
+	check("Merge", []int{2, 4}, nil)
+
+	other := NewSetWithOptions(cmp.Compare[int], opts...)
+	other.Insert(10)
+	other.Insert(11)
+	inserted = nil
+	if err := s.Join(other); err != nil {
+		t.Fatal(err)
+	}
+	check("Join", []int{10, 11}, []int{10, 11})
+
+	s.Clear()
+	check("Clear", nil, []int{2, 3, 4, 10, 11})
+}
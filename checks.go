@@ -0,0 +1,123 @@
+package set
+
+import "fmt"
+
+// ComparatorError describes a comparator that panicked or contradicted
+// itself on the keys A and B, and C where three keys are involved
+type ComparatorError struct {
+	A, B, C any
+	Reason  string
+	Panic   any // value recovered from a panicking comparator
+}
+
+// Error returns a description of the failure naming the keys involved
+func (e *ComparatorError) Error() string {
+	if e.Panic != nil {
+		return fmt.Sprintf("set: comparator panicked on (%v, %v): %v", e.A, e.B, e.Panic)
+	}
+	if e.C != nil {
+		return fmt.Sprintf("set: inconsistent comparator on (%v, %v, %v): %s", e.A, e.B, e.C, e.Reason)
+	}
+	return fmt.Sprintf("set: inconsistent comparator on (%v, %v): %s", e.A, e.B, e.Reason)
+}
+
+// Is reports whether target is ErrBadComparator
+func (e *ComparatorError) Is(target error) bool {
+	return target == ErrBadComparator
+}
+
+// Unwrap returns the recovered panic value if it is an error
+func (e *ComparatorError) Unwrap() error {
+	err, _ := e.Panic.(error)
+	return err
+}
+
+// WithComparatorChecks is a debugging aid that guards every comparison. A
+// panic in the comparator is re-raised as a *ComparatorError naming the
+// two keys, and every comparison is repeated with the keys swapped,
+// panicking with a *ComparatorError as soon as the results disagree.
+// Comparisons cost twice as much and more, so it is meant for tests and
+// for tracking down a suspected corrupt tree. It must be given after any
+// option replacing the comparator.
+func WithComparatorChecks[T any]() Option[T] {
+	return func(s *Set[T]) {
+		compare := s.compare
+		s.compare = func(a, b T) int {
+			ab := guardedCompare(compare, a, b)
+			if ba := guardedCompare(compare, b, a); sign(ab) != -sign(ba) {
+				panic(&ComparatorError{A: a, B: b, Reason: fmt.Sprintf("compare(a, b) = %d but compare(b, a) = %d", ab, ba)})
+			}
+			return ab
+		}
+	}
+}
+
+// CheckComparator cross-checks compare on every pair and triple of sample
+// for reflexivity, antisymmetry and transitivity, and returns a
+// *ComparatorError for the first violation found. It takes O(n³)
+// comparisons, so the sample should be a few dozen representative keys,
+// including edge cases such as zero values and keys that compare equal.
+func CheckComparator[T any](compare func(T, T) int, sample []T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ce, ok := r.(*ComparatorError)
+			if !ok {
+				panic(r)
+			}
+			err = ce
+		}
+	}()
+	for _, a := range sample {
+		if c := guardedCompare(compare, a, a); c != 0 {
+			return &ComparatorError{A: a, B: a, Reason: fmt.Sprintf("compare(a, a) = %d", c)}
+		}
+		for _, b := range sample {
+			ab, ba := guardedCompare(compare, a, b), guardedCompare(compare, b, a)
+			if sign(ab) != -sign(ba) {
+				return &ComparatorError{A: a, B: b, Reason: fmt.Sprintf("compare(a, b) = %d but compare(b, a) = %d", ab, ba)}
+			}
+			if ab > 0 {
+				continue
+			}
+			for _, c := range sample {
+				bc := guardedCompare(compare, b, c)
+				if bc > 0 {
+					continue
+				}
+				// a <= b <= c, so a <= c, with equality only if both are
+				want := 0
+				if ab < 0 || bc < 0 {
+					want = -1
+				}
+				if ac := guardedCompare(compare, a, c); sign(ac) != want {
+					return &ComparatorError{A: a, B: b, C: c, Reason: fmt.Sprintf("compare(a, b) = %d and compare(b, c) = %d but compare(a, c) = %d", ab, bc, ac)}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// guardedCompare calls compare, turning a panic into a *ComparatorError
+// that names the keys
+func guardedCompare[T any](compare func(T, T) int, a, b T) int {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(*ComparatorError); ok {
+				panic(r)
+			}
+			panic(&ComparatorError{A: a, B: b, Panic: r})
+		}
+	}()
+	return compare(a, b)
+}
+
+func sign(c int) int {
+	switch {
+	case c < 0:
+		return -1
+	case c > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package set
+
+import (
+	"cmp"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// recoverComparatorError runs fn and returns the *ComparatorError it
+// panicked with
+func recoverComparatorError(t *testing.T, fn func()) (ce *ComparatorError) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		var ok bool
+		if ce, ok = r.(*ComparatorError); !ok {
+			t.Fatalf("recovered %v, want a *ComparatorError", r)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestComparatorChecks(t *testing.T) {
+	// Equal ids compare greater both ways round
+	lopsided := func(a, b entry) int {
+		if a.id < b.id {
+			return -1
+		}
+		return 1
+	}
+	s := NewSetWithOptions(lopsided, WithComparatorChecks[entry]())
+	s.Insert(entry{1, "a"})
+	s.Insert(entry{2, "b"})
+	ce := recoverComparatorError(t, func() { s.Insert(entry{1, "c"}) })
+	if !errors.Is(ce, ErrBadComparator) || !strings.Contains(ce.Error(), "inconsistent comparator") {
+		t.Fatalf("error %v", ce)
+	}
+
+	// A panicking comparator is reported with the keys involved
+	cause := errors.New("no order for 13")
+	strict := func(a, b int) int {
+		if a == 13 || b == 13 {
+			panic(cause)
+		}
+		return cmp.Compare(a, b)
+	}
+	good := NewSetWithOptions(strict, WithComparatorChecks[int]())
+	good.Insert(1)
+	good.Insert(2)
+	ce = recoverComparatorError(t, func() { good.Insert(13) })
+	if !errors.Is(ce, cause) || ce.A != 13 && ce.B != 13 || !strings.Contains(ce.Error(), "panicked") {
+		t.Fatalf("error %v", ce)
+	}
+	if err := good.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckComparator(t *testing.T) {
+	sample := []int{0, -3, 7, 7, 2}
+	if err := CheckComparator(cmp.Compare[int], sample); err != nil {
+		t.Fatal(err)
+	}
+	notReflexive := func(a, b int) int {
+		if a <= b {
+			return -1
+		}
+		return 1
+	}
+	var ce *ComparatorError
+	if err := CheckComparator(notReflexive, sample); !errors.As(err, &ce) || ce.Reason != "compare(a, a) = -1" {
+		t.Fatalf("non-reflexive comparator: %v", err)
+	}
+	// Rock, paper, scissors
+	cyclic := func(a, b int) int {
+		if a == b {
+			return 0
+		}
+		if (a+1)%3 == b {
+			return -1
+		}
+		return 1
+	}
+	err := CheckComparator(cyclic, []int{0, 1, 2})
+	if !errors.As(err, &ce) || ce.C == nil || !errors.Is(err, ErrBadComparator) {
+		t.Fatalf("intransitive comparator: %v", err)
+	}
+	if err := CheckComparator(func(a, b int) int { panic("boom") }, sample); !errors.As(err, &ce) || ce.Panic != "boom" {
+		t.Fatalf("panicking comparator: %v", err)
+	}
+}
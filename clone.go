@@ -0,0 +1,39 @@
+package set
+
+// Clone returns a copy of s with the same comparator and key handling. The
+// tree is copied node for node, preserving shape and colors, so no
+// comparisons or rebalancing are done. Keys are shared with s; use
+// CloneWith to copy them as well.
+func (s *Set[T]) Clone() *Set[T] {
+	return s.CloneWith(nil)
+}
+
+// CloneWith is like Clone but passes every key through copyKey, which can
+// deep-copy keys holding pointers, slices or maps. A nil copyKey shares the
+// keys. The copy must order exactly like the original key.
+func (s *Set[T]) CloneWith(copyKey func(T) T) *Set[T] {
+	result := s.emptyCopy()
+	result.root = result.copyTree(s.root, nil, copyKey)
+	result.size = s.size
+	return result
+}
+
+// copyTree copies the subtree rooted at node below parent, taking the new
+// nodes from the node allocator of s
+func (s *Set[T]) copyTree(node, parent *Node[T], copyKey func(T) T) *Node[T] {
+	if node == nil {
+		return nil
+	}
+	key := node.key
+	if copyKey != nil {
+		key = copyKey(key)
+	}
+	result := s.newNode(key, node.color, parent)
+	result.left = s.copyTree(node.left, result, copyKey)
+	result.right = s.copyTree(node.right, result, copyKey)
+	result.size = node.size
+	if s.augment != nil {
+		s.augment(result)
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package set
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := intSet()
+	want := map[int]bool{}
+	randomOps(rng, s, want, 1000, 300)
+	c := s.Clone()
+	checkSet(t, c, want)
+	c.Insert(1000)
+	c.Remove(c.ToSlice()[0])
+	checkSet(t, s, want)
+}
+
+func TestCloneWith(t *testing.T) {
+	s := NewSet(func(a, b []int) int { return slices.Compare(a, b) })
+	s.Insert([]int{1, 2})
+	s.Insert([]int{3})
+	c := s.CloneWith(slices.Clone[[]int])
+	key, _ := c.Min()
+	key[1] = 20
+	if first, _ := s.Min(); first[1] != 2 {
+		t.Fatal("CloneWith shares keys")
+	}
+	if shared := s.Clone(); !slices.Equal(shared.ToSlice()[1], []int{3}) {
+		t.Fatal("Clone lost a key")
+	}
+}
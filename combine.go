@@ -0,0 +1,125 @@
+package set
+
+// combineOp selects which elements a CombineIterator yields
+type combineOp int
+
+const (
+	opUnion combineOp = iota
+	opIntersect
+	opDifference
+)
+
+// CombineIterator lazily walks two sets in order and yields the elements
+// of their union, intersection or difference, without building a result
+// set
+type CombineIterator[T any] struct {
+	op         combineOp
+	set, other *Set[T]
+	a, b       *Node[T]
+	key        T
+	valid      bool
+}
+
+// UnionIter returns an iterator over the elements in a or b in ascending
+// order. Where both sets hold equal elements the one from a is yielded.
+// Both sets are walked with the comparator of a and must not be modified
+// while the iterator is in use.
+func UnionIter[T any](a, b *Set[T]) *CombineIterator[T] {
+	return combine(opUnion, a, b)
+}
+
+// IntersectIter returns an iterator over the elements of a that are also
+// in b, in ascending order
+func IntersectIter[T any](a, b *Set[T]) *CombineIterator[T] {
+	return combine(opIntersect, a, b)
+}
+
+// DifferenceIter returns an iterator over the elements of a that are not
+// in b, in ascending order
+func DifferenceIter[T any](a, b *Set[T]) *CombineIterator[T] {
+	return combine(opDifference, a, b)
+}
+
+func combine[T any](op combineOp, a, b *Set[T]) *CombineIterator[T] {
+	it := &CombineIterator[T]{op: op, set: a, other: b}
+	if a.root != nil {
+		it.a = a.minimum(a.root)
+	}
+	if b.root != nil {
+		it.b = b.minimum(b.root)
+	}
+	it.advance()
+	return it
+}
+
+// Valid returns true if the iterator is positioned on an element
+func (it *CombineIterator[T]) Valid() bool {
+	return it.valid
+}
+
+// Value returns the current element
+func (it *CombineIterator[T]) Value() T {
+	return it.key
+}
+
+// Next moves to the next element
+func (it *CombineIterator[T]) Next() bool {
+	if !it.valid {
+		return false
+	}
+	it.advance()
+	return it.valid
+}
+
+// ToSlice collects the remaining elements
+func (it *CombineIterator[T]) ToSlice() []T {
+	var items []T
+	for ; it.valid; it.advance() {
+		items = append(items, it.key)
+	}
+	return items
+}
+
+func (it *CombineIterator[T]) advance() {
+	for it.a != nil && it.b != nil {
+		cmp := it.set.compare(it.a.key, it.b.key)
+		switch {
+		case cmp == 0:
+			it.key = it.a.key
+			it.a = it.set.successor(it.a)
+			it.b = it.other.successor(it.b)
+			if it.op != opDifference {
+				it.valid = true
+				return
+			}
+		case cmp < 0:
+			it.key = it.a.key
+			it.a = it.set.successor(it.a)
+			if it.op != opIntersect {
+				it.valid = true
+				return
+			}
+		default:
+			it.key = it.b.key
+			it.b = it.other.successor(it.b)
+			if it.op == opUnion {
+				it.valid = true
+				return
+			}
+		}
+	}
+	switch {
+	case it.a != nil && it.op != opIntersect:
+		it.key = it.a.key
+		it.a = it.set.successor(it.a)
+		it.valid = true
+	case it.b != nil && it.op == opUnion:
+		it.key = it.b.key
+		it.b = it.other.successor(it.b)
+		it.valid = true
+	default:
+		var zero T
+		it.key = zero
+		it.valid = false
+	}
+}
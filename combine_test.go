@@ -0,0 +1,60 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestCombineIterators(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		a, b := NewSet(cmp.Compare[int]), NewSet(cmp.Compare[int])
+		inA, inB := map[int]bool{}, map[int]bool{}
+		randomOps(rng, a, inA, rng.Intn(80), 60)
+		randomOps(rng, b, inB, rng.Intn(80), 60)
+		var union, inter, diff []int
+		for key := 0; key < 60; key++ {
+			if inA[key] || inB[key] {
+				union = append(union, key)
+			}
+			if inA[key] && inB[key] {
+				inter = append(inter, key)
+			}
+			if inA[key] && !inB[key] {
+				diff = append(diff, key)
+			}
+		}
+		if got := UnionIter(a, b).ToSlice(); !slices.Equal(got, union) {
+			t.Fatalf("UnionIter = %v, want %v", got, union)
+		}
+		if got := IntersectIter(a, b).ToSlice(); !slices.Equal(got, inter) {
+			t.Fatalf("IntersectIter = %v, want %v", got, inter)
+		}
+		var got []int
+		for it := DifferenceIter(a, b); it.Valid(); it.Next() {
+			got = append(got, it.Value())
+		}
+		if !slices.Equal(got, diff) {
+			t.Fatalf("DifferenceIter = %v, want %v", got, diff)
+		}
+	}
+}
+
+func TestUnionIterPrefersFirst(t *testing.T) {
+	a, b := NewSet(compareEntries), NewSet(compareEntries)
+	a.Insert(entry{1, "a"})
+	b.Insert(entry{1, "b"})
+	b.Insert(entry{2, "b"})
+	got := UnionIter(a, b).ToSlice()
+	if want := []entry{{1, "a"}, {2, "b"}}; !slices.Equal(got, want) {
+		t.Fatalf("UnionIter = %v, want %v", got, want)
+	}
+	if it := IntersectIter(b, a); it.Value() != (entry{1, "b"}) {
+		t.Fatalf("IntersectIter yielded %v", it.Value())
+	}
+	if it := UnionIter(NewSet(compareEntries), NewSet(compareEntries)); it.Valid() || it.Next() {
+		t.Fatal("the union of empty sets is not empty")
+	}
+}
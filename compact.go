@@ -0,0 +1,355 @@
+package set
+
+// CompactSet is a red-black tree set that keeps its nodes in a single
+// slice and links them with int32 indices instead of pointers. On 64-bit
+// platforms links and color take 16 bytes per node, against 48 for a Set
+// node, which also holds a subtree size and a generation. The garbage
+// collector sees one object instead of one per element, which matters for
+// sets of tens of millions of elements.
+//
+// CompactSet is a separate type, not an option of Set: the iterators,
+// views, snapshots and other extensions of Set hold node pointers, so it
+// offers the core set operations only.
+type CompactSet[T any] struct {
+	nodes   []compactNode[T] // nodes[0] is the black sentinel standing in for nil
+	root    int32
+	free    int32 // first free slot, linked through left, or 0
+	size    int
+	compare func(T, T) int
+}
+
+type compactNode[T any] struct {
+	key                 T
+	left, right, parent int32
+	color               Color
+}
+
+// NewCompactSet creates a new compact set with a custom comparator
+func NewCompactSet[T any](compare func(T, T) int) *CompactSet[T] {
+	requireComparator(compare)
+	return &CompactSet[T]{
+		nodes:   []compactNode[T]{{color: Black}},
+		compare: compare,
+	}
+}
+
+// Size returns the number of elements in the set
+func (c *CompactSet[T]) Size() int {
+	return c.size
+}
+
+// IsEmpty returns true if the set has no elements
+func (c *CompactSet[T]) IsEmpty() bool {
+	return c.size == 0
+}
+
+// Clear removes all elements from the set
+func (c *CompactSet[T]) Clear() {
+	clear(c.nodes)
+	c.nodes = c.nodes[:1]
+	c.nodes[0].color = Black
+	c.root, c.free, c.size = 0, 0, 0
+}
+
+// Insert adds a new element to the set
+func (c *CompactSet[T]) Insert(key T) bool {
+	parent, x, cmp := int32(0), c.root, 0
+	for x != 0 {
+		parent = x
+		cmp = c.compare(key, c.nodes[x].key)
+		if cmp == 0 {
+			return false
+		} else if cmp < 0 {
+			x = c.nodes[x].left
+		} else {
+			x = c.nodes[x].right
+		}
+	}
+	z := c.alloc(key, parent)
+	n := c.nodes
+	if parent == 0 {
+		c.root = z
+	} else if cmp < 0 {
+		n[parent].left = z
+	} else {
+		n[parent].right = z
+	}
+	c.size++
+	c.insertFixup(z)
+	return true
+}
+
+// Contains checks if an element exists in the set
+func (c *CompactSet[T]) Contains(key T) bool {
+	return c.find(key) != 0
+}
+
+// Remove removes an element from the set
+func (c *CompactSet[T]) Remove(key T) bool {
+	z := c.find(key)
+	if z == 0 {
+		return false
+	}
+	c.delete(z)
+	c.release(z)
+	c.size--
+	return true
+}
+
+// Min returns the smallest element
+func (c *CompactSet[T]) Min() (T, bool) {
+	if c.root == 0 {
+		var zero T
+		return zero, false
+	}
+	return c.nodes[c.minimum(c.root)].key, true
+}
+
+// Max returns the largest element
+func (c *CompactSet[T]) Max() (T, bool) {
+	if c.root == 0 {
+		var zero T
+		return zero, false
+	}
+	return c.nodes[c.maximum(c.root)].key, true
+}
+
+// Ascend calls fn for every element in ascending order until fn returns
+// false
+func (c *CompactSet[T]) Ascend(fn func(key T) bool) {
+	if c.root == 0 {
+		return
+	}
+	for x := c.minimum(c.root); x != 0; x = c.successor(x) {
+		if !fn(c.nodes[x].key) {
+			return
+		}
+	}
+}
+
+// ToSlice returns the elements in ascending order
+func (c *CompactSet[T]) ToSlice() []T {
+	keys := make([]T, 0, c.size)
+	c.Ascend(func(key T) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+func (c *CompactSet[T]) find(key T) int32 {
+	x := c.root
+	for x != 0 {
+		cmp := c.compare(key, c.nodes[x].key)
+		if cmp == 0 {
+			return x
+		} else if cmp < 0 {
+			x = c.nodes[x].left
+		} else {
+			x = c.nodes[x].right
+		}
+	}
+	return 0
+}
+
+// alloc returns the index of a new red leaf, reusing a free slot if there
+// is one. It may grow the node slice.
+func (c *CompactSet[T]) alloc(key T, parent int32) int32 {
+	node := compactNode[T]{key: key, parent: parent, color: Red}
+	if i := c.free; i != 0 {
+		c.free = c.nodes[i].left
+		c.nodes[i] = node
+		return i
+	}
+	c.nodes = append(c.nodes, node)
+	return int32(len(c.nodes) - 1)
+}
+
+// release puts slot i on the free list
+func (c *CompactSet[T]) release(i int32) {
+	c.nodes[i] = compactNode[T]{left: c.free}
+	c.free = i
+}
+
+func (c *CompactSet[T]) minimum(x int32) int32 {
+	for c.nodes[x].left != 0 {
+		x = c.nodes[x].left
+	}
+	return x
+}
+
+func (c *CompactSet[T]) maximum(x int32) int32 {
+	for c.nodes[x].right != 0 {
+		x = c.nodes[x].right
+	}
+	return x
+}
+
+func (c *CompactSet[T]) successor(x int32) int32 {
+	n := c.nodes
+	if n[x].right != 0 {
+		return c.minimum(n[x].right)
+	}
+	y := n[x].parent
+	for y != 0 && x == n[y].right {
+		x, y = y, n[y].parent
+	}
+	return y
+}
+
+func (c *CompactSet[T]) leftRotate(x int32) {
+	n := c.nodes
+	y := n[x].right
+	n[x].right = n[y].left
+	if n[y].left != 0 {
+		n[n[y].left].parent = x
+	}
+	c.replaceChild(n[x].parent, x, y)
+	n[y].left = x
+	n[x].parent = y
+}
+
+func (c *CompactSet[T]) rightRotate(x int32) {
+	n := c.nodes
+	y := n[x].left
+	n[x].left = n[y].right
+	if n[y].right != 0 {
+		n[n[y].right].parent = x
+	}
+	c.replaceChild(n[x].parent, x, y)
+	n[y].right = x
+	n[x].parent = y
+}
+
+// replaceChild makes v the child of parent in place of u, or the root if
+// parent is the sentinel. The sentinel itself may be linked in as v.
+func (c *CompactSet[T]) replaceChild(parent, u, v int32) {
+	n := c.nodes
+	switch {
+	case parent == 0:
+		c.root = v
+	case u == n[parent].left:
+		n[parent].left = v
+	default:
+		n[parent].right = v
+	}
+	n[v].parent = parent
+}
+
+func (c *CompactSet[T]) insertFixup(z int32) {
+	n := c.nodes
+	for n[n[z].parent].color == Red {
+		p := n[z].parent
+		g := n[p].parent
+		if p == n[g].left {
+			if u := n[g].right; n[u].color == Red {
+				n[p].color, n[u].color, n[g].color = Black, Black, Red
+				z = g
+				continue
+			}
+			if z == n[p].right {
+				z, p = p, z
+				c.leftRotate(z)
+			}
+			n[p].color, n[g].color = Black, Red
+			c.rightRotate(g)
+		} else {
+			if u := n[g].left; n[u].color == Red {
+				n[p].color, n[u].color, n[g].color = Black, Black, Red
+				z = g
+				continue
+			}
+			if z == n[p].left {
+				z, p = p, z
+				c.rightRotate(z)
+			}
+			n[p].color, n[g].color = Black, Red
+			c.leftRotate(g)
+		}
+	}
+	n[c.root].color = Black
+}
+
+func (c *CompactSet[T]) delete(z int32) {
+	n := c.nodes
+	var x int32
+	color := n[z].color
+	switch {
+	case n[z].left == 0:
+		x = n[z].right
+		c.replaceChild(n[z].parent, z, x)
+	case n[z].right == 0:
+		x = n[z].left
+		c.replaceChild(n[z].parent, z, x)
+	default:
+		y := c.minimum(n[z].right)
+		color = n[y].color
+		x = n[y].right
+		if n[y].parent == z {
+			// x may be the sentinel, whose parent the fixup relies on
+			n[x].parent = y
+		} else {
+			c.replaceChild(n[y].parent, y, x)
+			n[y].right = n[z].right
+			n[n[y].right].parent = y
+		}
+		c.replaceChild(n[z].parent, z, y)
+		n[y].left = n[z].left
+		n[n[y].left].parent = y
+		n[y].color = n[z].color
+	}
+	if color == Black {
+		c.deleteFixup(x)
+	}
+	n[0].parent = 0
+}
+
+func (c *CompactSet[T]) deleteFixup(x int32) {
+	n := c.nodes
+	for x != c.root && n[x].color == Black {
+		p := n[x].parent
+		if x == n[p].left {
+			w := n[p].right
+			if n[w].color == Red {
+				n[w].color, n[p].color = Black, Red
+				c.leftRotate(p)
+				w = n[p].right
+			}
+			if n[n[w].left].color == Black && n[n[w].right].color == Black {
+				n[w].color = Red
+				x = p
+				continue
+			}
+			if n[n[w].right].color == Black {
+				n[n[w].left].color, n[w].color = Black, Red
+				c.rightRotate(w)
+				w = n[p].right
+			}
+			n[w].color, n[p].color = n[p].color, Black
+			n[n[w].right].color = Black
+			c.leftRotate(p)
+		} else {
+			w := n[p].left
+			if n[w].color == Red {
+				n[w].color, n[p].color = Black, Red
+				c.rightRotate(p)
+				w = n[p].left
+			}
+			if n[n[w].left].color == Black && n[n[w].right].color == Black {
+				n[w].color = Red
+				x = p
+				continue
+			}
+			if n[n[w].left].color == Black {
+				n[n[w].right].color, n[w].color = Black, Red
+				c.leftRotate(w)
+				w = n[p].left
+			}
+			n[w].color, n[p].color = n[p].color, Black
+			n[n[w].left].color = Black
+			c.rightRotate(p)
+		}
+		x = c.root
+	}
+	n[x].color = Black
+}
@@ -0,0 +1,91 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkCompact fails t unless c holds exactly the keys of want and its
+// tree satisfies the red-black invariants
+func checkCompact(t *testing.T, c *CompactSet[int], want map[int]bool) {
+	t.Helper()
+	n := c.nodes
+	if n[0].color != Black || n[c.root].color != Black {
+		t.Fatal("the sentinel or the root is red")
+	}
+	var walk func(x, parent int32) int
+	walk = func(x, parent int32) int {
+		if x == 0 {
+			return 1
+		}
+		if n[x].parent != parent {
+			t.Fatalf("key %d has a wrong parent link", n[x].key)
+		}
+		for _, child := range []int32{n[x].left, n[x].right} {
+			if child != 0 && n[x].color == Red && n[child].color == Red {
+				t.Fatalf("red key %d has a red child", n[x].key)
+			}
+		}
+		lh, rh := walk(n[x].left, x), walk(n[x].right, x)
+		if lh != rh {
+			t.Fatalf("key %d has black heights %d and %d", n[x].key, lh, rh)
+		}
+		if n[x].color == Black {
+			lh++
+		}
+		return lh
+	}
+	walk(c.root, 0)
+	var keys []int
+	for key := range want {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if got := c.ToSlice(); !slices.Equal(got, keys) || c.Size() != len(keys) {
+		t.Fatalf("set holds %v, want %v", got, keys)
+	}
+}
+
+func TestCompactSet(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	c := NewCompactSet(cmp.Compare[int])
+	want := map[int]bool{}
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 500; i++ {
+			key := rng.Intn(300)
+			if rng.Intn(3) == 0 {
+				if c.Remove(key) != want[key] {
+					t.Fatalf("Remove(%d) disagrees", key)
+				}
+				delete(want, key)
+			} else {
+				if c.Insert(key) == want[key] {
+					t.Fatalf("Insert(%d) disagrees", key)
+				}
+				want[key] = true
+			}
+		}
+		checkCompact(t, c, want)
+	}
+	for key := 0; key < 300; key++ {
+		if c.Contains(key) != want[key] {
+			t.Fatalf("Contains(%d) disagrees", key)
+		}
+	}
+	// Removed slots are reused, so the slice never outgrows the peak size
+	if len(c.nodes) > 301 {
+		t.Fatalf("%d slots for at most 300 elements", len(c.nodes))
+	}
+	min, _ := c.Min()
+	max, _ := c.Max()
+	if keys := c.ToSlice(); min != keys[0] || max != keys[len(keys)-1] {
+		t.Fatalf("Min() = %d, Max() = %d", min, max)
+	}
+	c.Clear()
+	checkCompact(t, c, map[int]bool{})
+	if _, ok := c.Min(); ok || !c.IsEmpty() {
+		t.Fatal("Clear left elements")
+	}
+}
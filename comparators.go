@@ -0,0 +1,97 @@
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NewIntSet creates a new set of ints in ascending order
+func NewIntSet() *Set[int] {
+	return NewSet(CompareInt)
+}
+
+// NewStringSet creates a new set of strings in byte-wise order
+func NewStringSet() *Set[string] {
+	return NewSet(cmp.Compare[string])
+}
+
+// NewFloat64Set creates a new set of float64s in ascending order. NaN
+// sorts before every other value and all NaNs are treated as equal.
+func NewFloat64Set() *Set[float64] {
+	return NewSet(cmp.Compare[float64])
+}
+
+// NewTimeSet creates a new set of instants in chronological order. Times
+// that denote the same instant in different locations are equal.
+func NewTimeSet() *Set[time.Time] {
+	return NewSet(CompareTime)
+}
+
+// NewBytesSet creates a new set of byte slices in lexicographic order. The
+// slices are stored as given and must not be modified afterwards; combine
+// NewSetWithOptions with WithKeyClone to store copies instead.
+func NewBytesSet() *Set[[]byte] {
+	return NewSet(bytes.Compare)
+}
+
+// CompareInt compares two ints without the overflow of a - b
+func CompareInt(a, b int) int {
+	return cmp.Compare(a, b)
+}
+
+// CompareTime compares two instants
+func CompareTime(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+// CompareStringFold compares two strings case-insensitively under Unicode
+// simple case folding, rune by rune and without allocating
+func CompareStringFold(a, b string) int {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			if c := cmp.Compare(foldRune(ra), foldRune(rb)); c != 0 {
+				return c
+			}
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// foldRune maps r to the smallest rune of its case folding orbit, so that
+// all case variants of a letter compare equal
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
+
+// Reverse returns a comparator ordering elements the opposite way to
+// compare
+func Reverse[T any](compare func(T, T) int) func(T, T) int {
+	return func(a, b T) int {
+		return compare(b, a)
+	}
+}
+
+// ChainComparators returns a comparator that orders by the first of
+// compares and breaks ties with the following ones in turn
+func ChainComparators[T any](compares ...func(T, T) int) func(T, T) int {
+	return func(a, b T) int {
+		for _, compare := range compares {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
@@ -0,0 +1,77 @@
+package set
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuiltinSets(t *testing.T) {
+	ints := NewIntSet()
+	ints.Insert(math.MaxInt)
+	ints.Insert(math.MinInt)
+	if min, _ := ints.Min(); min != math.MinInt {
+		t.Fatal("CompareInt overflowed")
+	}
+	floats := NewFloat64Set()
+	floats.Insert(1)
+	floats.Insert(math.NaN())
+	floats.Insert(math.NaN())
+	if min, _ := floats.Min(); floats.Size() != 2 || !math.IsNaN(min) {
+		t.Fatalf("NaNs were not collapsed before the numbers: %v", floats.ToSlice())
+	}
+	times := NewTimeSet()
+	now := time.Now()
+	times.Insert(now)
+	if times.Insert(now.In(time.FixedZone("elsewhere", 3600))) {
+		t.Fatal("the same instant in another location was inserted")
+	}
+	seq := NewBytesSet()
+	seq.Insert([]byte("b"))
+	seq.Insert([]byte("ab"))
+	if first, _ := seq.Min(); string(first) != "ab" {
+		t.Fatal("byte slices are out of order")
+	}
+	words := NewStringSet()
+	words.Insert("b")
+	words.Insert("B")
+	if first, _ := words.Min(); first != "B" {
+		t.Fatal("strings are not in byte-wise order")
+	}
+}
+
+func TestCompareStringFold(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{
+		{"Hello", "hELLO", 0},
+		{"straße", "STRASSE", 1}, // ß folds to itself, not to "ss"
+		{"ΣΑΣ", "σας", 0},
+		{"apple", "Banana", -1},
+		{"ab", "AB c", -1},
+		{"", "", 0},
+	} {
+		if got := CompareStringFold(c.a, c.b); got != c.want {
+			t.Errorf("CompareStringFold(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestComposedComparators(t *testing.T) {
+	words := []string{"pear", "fig", "apple", "kiwi", "date"}
+	byLen := func(a, b string) int { return cmp.Compare(len(a), len(b)) }
+	s := NewSet(ChainComparators(Reverse(byLen), strings.Compare))
+	for _, w := range words {
+		s.Insert(w)
+	}
+	if got, want := s.ToSlice(), []string{"apple", "date", "kiwi", "pear", "fig"}; !slices.Equal(got, want) {
+		t.Fatalf("chained order = %v, want %v", got, want)
+	}
+	if ChainComparators[int]()(1, 2) != 0 {
+		t.Fatal("an empty chain did not treat everything as equal")
+	}
+}
@@ -0,0 +1,76 @@
+package set
+
+import (
+	"container/heap"
+	"strings"
+)
+
+// Complete returns up to k elements of s that start with prefix. The set
+// must be ordered byte-wise, as with strings.Compare. With a nil score
+// the first k completions are returned in order; otherwise the k highest
+// scoring completions are returned best first, ties going to the smaller
+// string.
+func Complete(s *Set[string], prefix string, k int, score func(string) float64) []string {
+	if k <= 0 {
+		return nil
+	}
+	prefix = s.canonical(prefix)
+	var out []string
+	best := &completionHeap{}
+	for node := s.lowerBound(prefix); node != nil; node = s.successor(node) {
+		key := node.key
+		if !strings.HasPrefix(key, prefix) {
+			break
+		}
+		if score == nil {
+			out = append(out, key)
+			if len(out) == k {
+				break
+			}
+			continue
+		}
+		c := completion{key: key, score: score(key)}
+		if best.Len() < k {
+			heap.Push(best, c)
+		} else if c.score > (*best)[0].score {
+			(*best)[0] = c
+			heap.Fix(best, 0)
+		}
+	}
+	if score == nil {
+		return out
+	}
+	out = make([]string, best.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(best).(completion).key
+	}
+	return out
+}
+
+type completion struct {
+	key   string
+	score float64
+}
+
+// completionHeap is a min-heap keeping the worst retained completion on top
+type completionHeap []completion
+
+func (h completionHeap) Len() int { return len(h) }
+
+func (h completionHeap) Less(i, j int) bool {
+	if h[i].score != h[j].score {
+		return h[i].score < h[j].score
+	}
+	return h[i].key > h[j].key
+}
+
+func (h completionHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *completionHeap) Push(x any) { *h = append(*h, x.(completion)) }
+
+func (h *completionHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
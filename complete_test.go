@@ -0,0 +1,31 @@
+package set
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	s := NewSet(strings.Compare)
+	for _, w := range []string{"car", "cart", "carbon", "care", "cat", "ca", "dog"} {
+		s.Insert(w)
+	}
+	if got := Complete(s, "car", 3, nil); !slices.Equal(got, []string{"car", "carbon", "care"}) {
+		t.Fatalf("unscored completions = %v", got)
+	}
+	if got := Complete(s, "x", 3, nil); got != nil {
+		t.Fatalf("completions of a missing prefix = %v", got)
+	}
+	if got := Complete(s, "car", 0, nil); got != nil {
+		t.Fatalf("k = 0 returned %v", got)
+	}
+	// Longest first; "care" and "cart" tie and the smaller string wins
+	byLen := func(w string) float64 { return float64(len(w)) }
+	if got := Complete(s, "car", 2, byLen); !slices.Equal(got, []string{"carbon", "care"}) {
+		t.Fatalf("scored completions = %v", got)
+	}
+	if got := Complete(s, "ca", 10, byLen); !slices.Equal(got, []string{"carbon", "care", "cart", "car", "cat", "ca"}) {
+		t.Fatalf("all scored completions = %v", got)
+	}
+}
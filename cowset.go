@@ -0,0 +1,106 @@
+package set
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// COWSet is a copy-on-write set for read-heavy concurrent use. The
+// contents are held as an immutable PersistentSet; writers copy the path
+// to the change and atomically swap in the new version, so readers never
+// lock and always see a consistent snapshot. Writers are serialized with a
+// mutex among themselves only.
+type COWSet[T any] struct {
+	mu      sync.Mutex
+	current atomic.Pointer[PersistentSet[T]]
+}
+
+// NewCOWSet creates a new copy-on-write set with a custom comparator
+func NewCOWSet[T any](compare func(T, T) int) *COWSet[T] {
+	c := &COWSet[T]{}
+	c.current.Store(NewPersistentSet(compare))
+	return c
+}
+
+// Snapshot returns the current version of the set. It is immutable and
+// stays valid regardless of later writes.
+func (c *COWSet[T]) Snapshot() *PersistentSet[T] {
+	return c.current.Load()
+}
+
+// Size returns the number of elements in the set
+func (c *COWSet[T]) Size() int {
+	return c.Snapshot().Size()
+}
+
+// IsEmpty returns true if the set has no elements
+func (c *COWSet[T]) IsEmpty() bool {
+	return c.Snapshot().IsEmpty()
+}
+
+// Contains checks if an element exists in the set
+func (c *COWSet[T]) Contains(key T) bool {
+	return c.Snapshot().Contains(key)
+}
+
+// Find returns the element stored in the set that is equal to key
+func (c *COWSet[T]) Find(key T) (T, bool) {
+	return c.Snapshot().Find(key)
+}
+
+// Min returns the smallest element in the set
+func (c *COWSet[T]) Min() (T, bool) {
+	return c.Snapshot().Min()
+}
+
+// Max returns the largest element in the set
+func (c *COWSet[T]) Max() (T, bool) {
+	return c.Snapshot().Max()
+}
+
+// Ascend calls fn for every element of the current version in ascending
+// order until fn returns false. Writes made by fn are not observed.
+func (c *COWSet[T]) Ascend(fn func(key T) bool) {
+	c.Snapshot().Ascend(fn)
+}
+
+// ToSlice returns all elements in ascending order
+func (c *COWSet[T]) ToSlice() []T {
+	return c.Snapshot().ToSlice()
+}
+
+// Insert adds a new element to the set
+func (c *COWSet[T]) Insert(key T) bool {
+	return c.Update(func(p *PersistentSet[T]) *PersistentSet[T] {
+		return p.Insert(key)
+	})
+}
+
+// Remove removes an element from the set
+func (c *COWSet[T]) Remove(key T) bool {
+	return c.Update(func(p *PersistentSet[T]) *PersistentSet[T] {
+		return p.Remove(key)
+	})
+}
+
+// Clear removes all elements from the set
+func (c *COWSet[T]) Clear() {
+	c.Update(func(p *PersistentSet[T]) *PersistentSet[T] {
+		return NewPersistentSet(p.compare)
+	})
+}
+
+// Update applies fn to the current version under the writer lock and
+// publishes the version it returns, so several changes become visible to
+// readers at once. It reports whether the version changed.
+func (c *COWSet[T]) Update(fn func(*PersistentSet[T]) *PersistentSet[T]) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old := c.current.Load()
+	next := fn(old)
+	if next == old {
+		return false
+	}
+	c.current.Store(next)
+	return true
+}
@@ -0,0 +1,84 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestCOWSet(t *testing.T) {
+	c := NewCOWSet(cmp.Compare[int])
+	if !c.Insert(2) || !c.Insert(1) || c.Insert(2) {
+		t.Fatal("Insert misreported whether the set changed")
+	}
+	before := c.Snapshot()
+	if !c.Remove(1) || c.Remove(1) {
+		t.Fatal("Remove misreported whether the set changed")
+	}
+	if !slices.Equal(before.ToSlice(), []int{1, 2}) {
+		t.Fatalf("a snapshot changed to %v", before.ToSlice())
+	}
+	if c.Size() != 1 || !c.Contains(2) || c.Contains(1) {
+		t.Fatalf("set holds %v", c.ToSlice())
+	}
+
+	// A batch of changes is published as one version
+	c.Update(func(p *PersistentSet[int]) *PersistentSet[int] {
+		for i := 10; i < 15; i++ {
+			p = p.Insert(i)
+		}
+		return p
+	})
+	if lo, _ := c.Min(); lo != 2 {
+		t.Fatalf("Min = %d", lo)
+	}
+	if hi, _ := c.Max(); hi != 14 {
+		t.Fatalf("Max = %d", hi)
+	}
+	c.Clear()
+	if !c.IsEmpty() {
+		t.Fatalf("Clear left %v", c.ToSlice())
+	}
+}
+
+func TestCOWSetConcurrentReaders(t *testing.T) {
+	// Every version holds the keys 0..n-1 for some multiple n of 4, which
+	// readers check while a writer grows the set four keys at a time
+	c := NewCOWSet(cmp.Compare[int])
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				keys := c.ToSlice()
+				for i, key := range keys {
+					if key != i || len(keys)%4 != 0 {
+						t.Errorf("a reader saw the torn version %v", keys)
+						return
+					}
+				}
+			}
+		}()
+	}
+	for n := 0; n < 200; n += 4 {
+		c.Update(func(p *PersistentSet[int]) *PersistentSet[int] {
+			for i := n; i < n+4; i++ {
+				p = p.Insert(i)
+			}
+			return p
+		})
+	}
+	close(done)
+	wg.Wait()
+	if c.Size() != 200 {
+		t.Fatalf("Size = %d, want 200", c.Size())
+	}
+}
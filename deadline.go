@@ -0,0 +1,111 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLockTimeout is returned by the Try and Context methods of SyncSet
+// when the lock could not be taken in time. It wraps the error of the
+// context, so errors.Is also matches context.DeadlineExceeded or
+// context.Canceled.
+var ErrLockTimeout = errors.New("set: timed out waiting for lock")
+
+// maxLockWait caps the pause between two attempts to take a contended lock
+const maxLockWait = time.Millisecond
+
+// SetLockTimeout sets how long TryInsert, TryRemove and TryContains wait
+// for the lock before giving up with ErrLockTimeout. The default of zero
+// makes them fail at once if the lock is held.
+func (s *SyncSet[T]) SetLockTimeout(d time.Duration) {
+	s.lockTimeout.Store(int64(d))
+}
+
+// LockTimeout returns the wait set by SetLockTimeout
+func (s *SyncSet[T]) LockTimeout() time.Duration {
+	return time.Duration(s.lockTimeout.Load())
+}
+
+// TryInsert is like Insert but waits at most LockTimeout for the lock
+func (s *SyncSet[T]) TryInsert(key T) (bool, error) {
+	ctx, cancel := s.timeoutContext()
+	defer cancel()
+	return s.InsertContext(ctx, key)
+}
+
+// TryRemove is like Remove but waits at most LockTimeout for the lock
+func (s *SyncSet[T]) TryRemove(key T) (bool, error) {
+	ctx, cancel := s.timeoutContext()
+	defer cancel()
+	return s.RemoveContext(ctx, key)
+}
+
+// TryContains is like Contains but waits at most LockTimeout for the lock
+func (s *SyncSet[T]) TryContains(key T) (bool, error) {
+	ctx, cancel := s.timeoutContext()
+	defer cancel()
+	return s.ContainsContext(ctx, key)
+}
+
+// InsertContext is like Insert but gives up with ErrLockTimeout if ctx is
+// done before the lock is taken
+func (s *SyncSet[T]) InsertContext(ctx context.Context, key T) (bool, error) {
+	unlock, err := s.lockContext(ctx, true)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	return s.set.Insert(key), nil
+}
+
+// RemoveContext is like Remove but gives up with ErrLockTimeout if ctx is
+// done before the lock is taken
+func (s *SyncSet[T]) RemoveContext(ctx context.Context, key T) (bool, error) {
+	unlock, err := s.lockContext(ctx, true)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	return s.set.Remove(key), nil
+}
+
+// ContainsContext is like Contains but gives up with ErrLockTimeout if ctx
+// is done before the lock is taken
+func (s *SyncSet[T]) ContainsContext(ctx context.Context, key T) (bool, error) {
+	unlock, err := s.lockContext(ctx, s.set.loader != nil)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	return s.set.Contains(key), nil
+}
+
+func (s *SyncSet[T]) timeoutContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.LockTimeout())
+}
+
+// lockContext takes the write or read lock, polling with growing pauses
+// until it succeeds or ctx is done, and returns its unlock. A timed writer
+// does not hold back new readers the way a blocked Lock does, so under a
+// steady stream of readers it may time out even though no one holds the
+// lock for long.
+func (s *SyncSet[T]) lockContext(ctx context.Context, write bool) (func(), error) {
+	try, unlock := s.mu.TryRLock, s.mu.RUnlock
+	if write {
+		try, unlock = s.mu.TryLock, s.mu.Unlock
+	}
+	wait := time.Microsecond
+	for !try() {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w: %w", ErrLockTimeout, ctx.Err())
+		case <-timer.C:
+		}
+		wait = min(2*wait, maxLockWait)
+	}
+	return unlock, nil
+}
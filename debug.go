@@ -0,0 +1,72 @@
+package set
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// DOT writes the tree in Graphviz DOT format, with red and black nodes
+// filled in their color and labeled with their key and subtree size
+func (s *Set[T]) DOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph set {")
+	fmt.Fprintln(bw, "\tnode [style=filled, fontcolor=white, shape=circle];")
+	ids := 0
+	var walk func(node *Node[T]) int
+	walk = func(node *Node[T]) int {
+		id := ids
+		ids++
+		fill := "black"
+		if node.color == Red {
+			fill = "red"
+		}
+		fmt.Fprintf(bw, "\tn%d [label=%q, fillcolor=%s];\n", id, fmt.Sprintf("%v\n%d", node.key, node.size), fill)
+		for _, child := range [...]*Node[T]{node.left, node.right} {
+			if child != nil {
+				fmt.Fprintf(bw, "\tn%d -> n%d;\n", id, walk(child))
+			}
+		}
+		return id
+	}
+	if s.root != nil {
+		walk(s.root)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// DumpTree writes an indented drawing of the tree, one node per line with
+// its key and color, left children before right ones
+func (s *Set[T]) DumpTree(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if s.root == nil {
+		fmt.Fprintln(bw, "(empty)")
+		return bw.Flush()
+	}
+	var walk func(node *Node[T], prefix, branch, side string)
+	walk = func(node *Node[T], prefix, branch, side string) {
+		if node == nil {
+			fmt.Fprintf(bw, "%s%s%s·\n", prefix, branch, side)
+			return
+		}
+		color := "B"
+		if node.color == Red {
+			color = "R"
+		}
+		fmt.Fprintf(bw, "%s%s%s%v (%s)\n", prefix, branch, side, node.key, color)
+		if node.left == nil && node.right == nil {
+			return
+		}
+		switch branch {
+		case "├── ":
+			prefix += "│   "
+		case "└── ":
+			prefix += "    "
+		}
+		walk(node.left, prefix, "├── ", "L: ")
+		walk(node.right, prefix, "└── ", "R: ")
+	}
+	walk(s.root, "", "", "")
+	return bw.Flush()
+}
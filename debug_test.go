@@ -0,0 +1,48 @@
+package set
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDOT(t *testing.T) {
+	var b strings.Builder
+	if err := intSet(1, 2, 3, 4).DOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph set {
+	node [style=filled, fontcolor=white, shape=circle];
+	n0 [label="2\n4", fillcolor=black];
+	n1 [label="1\n1", fillcolor=black];
+	n0 -> n1;
+	n2 [label="3\n2", fillcolor=black];
+	n3 [label="4\n1", fillcolor=red];
+	n2 -> n3;
+	n0 -> n2;
+}
+`
+	if b.String() != want {
+		t.Fatalf("DOT wrote\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestDumpTree(t *testing.T) {
+	var b strings.Builder
+	if err := intSet(1, 2, 3, 4).DumpTree(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `2 (B)
+├── L: 1 (B)
+└── R: 3 (B)
+    ├── L: ·
+    └── R: 4 (R)
+`
+	if b.String() != want {
+		t.Fatalf("DumpTree wrote\n%s\nwant\n%s", b.String(), want)
+	}
+	b.Reset()
+	intSet().DumpTree(&b)
+	if b.String() != "(empty)\n" {
+		t.Fatalf("DumpTree of an empty set wrote %q", b.String())
+	}
+}
@@ -0,0 +1,147 @@
+package set
+
+import (
+	"math"
+	"time"
+)
+
+// DecayingMultiSet counts occurrences of elements with weights that decay
+// exponentially over time, so recent occurrences dominate. It is meant for
+// trending-item and heavy-hitter detection.
+//
+// Weights are stored relative to a landmark time and grow with each
+// occurrence instead of shrinking with age, which keeps the ordering by
+// weight stable without touching every element as time passes.
+type DecayingMultiSet[T any] struct {
+	halfLife time.Duration
+	now      func() time.Time
+	landmark time.Time
+	byKey    *Set[*decayEntry[T]]
+	byWeight *Set[*decayEntry[T]]
+}
+
+type decayEntry[T any] struct {
+	key    T
+	weight float64
+}
+
+// DecayedCount is an element together with its decayed occurrence count
+type DecayedCount[T any] struct {
+	Key   T
+	Count float64
+}
+
+// maxDecayExponent bounds the relative weight growth before the landmark
+// is moved forward to keep weights in floating point range
+const maxDecayExponent = 512
+
+// NewDecayingMultiSet creates a multiset ordering elements with compare in
+// which every occurrence loses half its weight after halfLife. It panics
+// if halfLife is not positive.
+func NewDecayingMultiSet[T any](compare func(T, T) int, halfLife time.Duration) *DecayingMultiSet[T] {
+	requireComparator(compare)
+	if halfLife <= 0 {
+		panic("set: half-life must be positive")
+	}
+	m := &DecayingMultiSet[T]{
+		halfLife: halfLife,
+		now:      time.Now,
+	}
+	m.landmark = m.now()
+	m.byKey = NewSet(func(a, b *decayEntry[T]) int {
+		return compare(a.key, b.key)
+	})
+	m.byWeight = NewSet(func(ea, eb *decayEntry[T]) int {
+		switch {
+		case ea.weight > eb.weight:
+			return -1
+		case ea.weight < eb.weight:
+			return 1
+		}
+		return compare(ea.key, eb.key)
+	})
+	return m
+}
+
+// Len returns the number of distinct elements tracked
+func (m *DecayingMultiSet[T]) Len() int {
+	return m.byKey.Size()
+}
+
+// Add records one occurrence of key
+func (m *DecayingMultiSet[T]) Add(key T) {
+	exp := m.exponent()
+	if exp > maxDecayExponent {
+		m.rebase()
+		exp = 0
+	}
+	if node := m.byKey.find(&decayEntry[T]{key: key}); node != nil {
+		e := node.key
+		m.byWeight.Remove(e)
+		e.weight += math.Exp2(exp)
+		m.byWeight.Insert(e)
+		return
+	}
+	e := &decayEntry[T]{key: key, weight: math.Exp2(exp)}
+	m.byKey.Insert(e)
+	m.byWeight.Insert(e)
+}
+
+// Count returns the decayed occurrence count of key
+func (m *DecayingMultiSet[T]) Count(key T) float64 {
+	node := m.byKey.find(&decayEntry[T]{key: key})
+	if node == nil {
+		return 0
+	}
+	return node.key.weight * math.Exp2(-m.exponent())
+}
+
+// TopK returns up to n elements with the highest decayed counts, highest
+// first
+func (m *DecayingMultiSet[T]) TopK(n int) []DecayedCount[T] {
+	scale := math.Exp2(-m.exponent())
+	var top []DecayedCount[T]
+	for it := m.byWeight.Begin(); it.Valid() && len(top) < n; it.Next() {
+		e := it.Value()
+		top = append(top, DecayedCount[T]{Key: e.key, Count: e.weight * scale})
+	}
+	return top
+}
+
+// Prune forgets all elements whose decayed count dropped below min and
+// returns how many were removed
+func (m *DecayingMultiSet[T]) Prune(min float64) int {
+	threshold := min * math.Exp2(m.exponent())
+	var stale []*decayEntry[T]
+	for it := m.byWeight.RBegin(); it.Valid(); it.Next() {
+		e := it.Value()
+		if e.weight >= threshold {
+			break
+		}
+		stale = append(stale, e)
+	}
+	for _, e := range stale {
+		m.byWeight.Remove(e)
+		m.byKey.Remove(e)
+	}
+	return len(stale)
+}
+
+// exponent returns the number of half-lives elapsed since the landmark
+func (m *DecayingMultiSet[T]) exponent() float64 {
+	return float64(m.now().Sub(m.landmark)) / float64(m.halfLife)
+}
+
+// rebase moves the landmark to the current time and rescales all weights.
+// Rescaling can round distinct weights to the same value, so the weight
+// order is rebuilt rather than assumed to be preserved.
+func (m *DecayingMultiSet[T]) rebase() {
+	scale := math.Exp2(-m.exponent())
+	m.landmark = m.now()
+	m.byWeight.Clear()
+	for it := m.byKey.Begin(); it.Valid(); it.Next() {
+		e := it.Value()
+		e.weight *= scale
+		m.byWeight.Insert(e)
+	}
+}
@@ -0,0 +1,51 @@
+package set
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecayingMultiSet(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewDecayingMultiSet(strings.Compare, time.Minute)
+	m.now, m.landmark = func() time.Time { return now }, now
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
+
+	m.Add("a")
+	m.Add("a")
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		m.Add("b")
+	}
+	if !near(m.Count("a"), 1) || !near(m.Count("b"), 3) || m.Count("c") != 0 {
+		t.Fatalf("counts a=%v b=%v", m.Count("a"), m.Count("b"))
+	}
+	top := m.TopK(5)
+	if len(top) != 2 || top[0].Key != "b" || top[1].Key != "a" || !near(top[1].Count, 1) {
+		t.Fatalf("TopK = %v", top)
+	}
+	if n := m.Prune(1.5); n != 1 || m.Len() != 1 || m.Count("a") != 0 {
+		t.Fatalf("Prune removed %d, %d left", n, m.Len())
+	}
+
+	// Far past the landmark the weights are rebased instead of overflowing
+	now = now.Add(2 * maxDecayExponent * time.Minute)
+	m.Add("c")
+	if !near(m.Count("c"), 1) || m.Count("b") > 1e-100 {
+		t.Fatalf("after a rebase c=%v b=%v", m.Count("c"), m.Count("b"))
+	}
+	if top := m.TopK(1); len(top) != 1 || top[0].Key != "c" {
+		t.Fatalf("TopK after a rebase = %v", top)
+	}
+}
+
+func TestDecayingMultiSetHalfLife(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("a zero half-life was accepted")
+		}
+	}()
+	NewDecayingMultiSet(strings.Compare, 0)
+}
@@ -0,0 +1,50 @@
+package set
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Dedupe streams newline-delimited records from r to w, dropping every
+// record already present in the set. Each line is decoded with parse and
+// written back with format the first time it is seen, so output keeps the
+// input order. Seen records are inserted into s, which can be pre-seeded
+// to filter against known records. It returns the number of records
+// written.
+//
+// Nothing is ever evicted: s keeps every distinct record, so memory grows
+// without bound with the number of distinct records in the stream. To cap
+// it, create s WithMaxSize; Dedupe then stops with an error matching
+// ErrFull at the first new record that does not fit.
+func (s *Set[T]) Dedupe(r io.Reader, w io.Writer, parse func(string) (T, error), format func(T) string) (int, error) {
+	scanner := bufio.NewScanner(r)
+	out := bufio.NewWriter(w)
+	written := 0
+	for scanner.Scan() {
+		record, err := parse(scanner.Text())
+		if err != nil {
+			out.Flush()
+			return written, err
+		}
+		if !s.Insert(record) {
+			if s.find(s.canonical(record)) == nil {
+				out.Flush()
+				return written, fmt.Errorf("%w: %v", ErrFull, record)
+			}
+			continue
+		}
+		if _, err := out.WriteString(format(record)); err != nil {
+			return written, err
+		}
+		if err := out.WriteByte('\n'); err != nil {
+			return written, err
+		}
+		written++
+	}
+	if err := scanner.Err(); err != nil {
+		out.Flush()
+		return written, err
+	}
+	return written, out.Flush()
+}
@@ -0,0 +1,35 @@
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDedupe(t *testing.T) {
+	s := intSet(7)
+	var out bytes.Buffer
+	n, err := s.Dedupe(strings.NewReader("3\n1\n3\n7\n2\n1\n"), &out, strconv.Atoi, strconv.Itoa)
+	if err != nil || n != 3 || out.String() != "3\n1\n2\n" {
+		t.Fatalf("Dedupe wrote %d records %q, %v", n, out.String(), err)
+	}
+	checkSet(t, s, map[int]bool{1: true, 2: true, 3: true, 7: true})
+
+	out.Reset()
+	n, err = s.Dedupe(strings.NewReader("4\nx\n5\n"), &out, strconv.Atoi, strconv.Itoa)
+	if err == nil || n != 1 || out.String() != "4\n" {
+		t.Fatalf("a bad record: wrote %d records %q, %v", n, out.String(), err)
+	}
+}
+
+func TestDedupeFull(t *testing.T) {
+	s := NewSetWithOptions(cmp.Compare[int], WithMaxSize[int](2))
+	var out bytes.Buffer
+	n, err := s.Dedupe(strings.NewReader("1\n2\n1\n3\n4\n"), &out, strconv.Atoi, strconv.Itoa)
+	if !errors.Is(err, ErrFull) || n != 2 || out.String() != "1\n2\n" {
+		t.Fatalf("Dedupe into a full set wrote %d records %q, %v", n, out.String(), err)
+	}
+}
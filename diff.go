@@ -0,0 +1,108 @@
+package set
+
+// DiffKind identifies the kind of a diff operation
+type DiffKind int
+
+const (
+	// DiffAdd means the key is present in the other set but not in this one
+	DiffAdd DiffKind = iota
+	// DiffRemove means the key is present in this set but not in the other one
+	DiffRemove
+)
+
+// DiffOp is a single operation that moves one set towards another
+type DiffOp[T any] struct {
+	Kind DiffKind
+	Key  T
+}
+
+// DiffIterator lazily walks two sets in order and yields the operations
+// that turn the first set into the second one
+type DiffIterator[T any] struct {
+	set, other *Set[T]
+	a, b       *Node[T]
+	op         DiffOp[T]
+	valid      bool
+}
+
+// DiffIter returns an iterator over the Add/Remove operations that turn s
+// into other. Both sets are walked in order using the comparator of s, so
+// no intermediate result is materialized. The sets must not be modified
+// while the iterator is in use.
+func (s *Set[T]) DiffIter(other *Set[T]) *DiffIterator[T] {
+	it := &DiffIterator[T]{set: s, other: other}
+	if s.root != nil {
+		it.a = s.minimum(s.root)
+	}
+	if other.root != nil {
+		it.b = other.minimum(other.root)
+	}
+	it.advance()
+	return it
+}
+
+// Diff returns the elements of new that are missing from old and the
+// elements of old that are missing from new, each in ascending order. Both
+// sets are walked once in order with the comparator of old.
+func Diff[T any](old, new *Set[T]) (added, removed []T) {
+	for it := old.DiffIter(new); it.Valid(); it.Next() {
+		op := it.Op()
+		if op.Kind == DiffAdd {
+			added = append(added, op.Key)
+		} else {
+			removed = append(removed, op.Key)
+		}
+	}
+	return added, removed
+}
+
+// Valid returns true if the iterator is positioned on an operation
+func (it *DiffIterator[T]) Valid() bool {
+	return it.valid
+}
+
+// Op returns the current operation
+func (it *DiffIterator[T]) Op() DiffOp[T] {
+	return it.op
+}
+
+// Next moves to the next operation
+func (it *DiffIterator[T]) Next() bool {
+	if !it.valid {
+		return false
+	}
+	it.advance()
+	return it.valid
+}
+
+func (it *DiffIterator[T]) advance() {
+	for it.a != nil && it.b != nil {
+		cmp := it.set.compare(it.a.key, it.b.key)
+		if cmp == 0 {
+			it.a = it.set.successor(it.a)
+			it.b = it.other.successor(it.b)
+			continue
+		}
+		if cmp < 0 {
+			it.op = DiffOp[T]{Kind: DiffRemove, Key: it.a.key}
+			it.a = it.set.successor(it.a)
+		} else {
+			it.op = DiffOp[T]{Kind: DiffAdd, Key: it.b.key}
+			it.b = it.other.successor(it.b)
+		}
+		it.valid = true
+		return
+	}
+	switch {
+	case it.a != nil:
+		it.op = DiffOp[T]{Kind: DiffRemove, Key: it.a.key}
+		it.a = it.set.successor(it.a)
+		it.valid = true
+	case it.b != nil:
+		it.op = DiffOp[T]{Kind: DiffAdd, Key: it.b.key}
+		it.b = it.other.successor(it.b)
+		it.valid = true
+	default:
+		it.valid = false
+	}
+}
@@ -0,0 +1,51 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDiffIter(t *testing.T) {
+	old, new := intSet(1, 2, 4, 6), intSet(2, 3, 4, 7, 8)
+	var ops []DiffOp[int]
+	for it := old.DiffIter(new); it.Valid(); it.Next() {
+		ops = append(ops, it.Op())
+	}
+	want := []DiffOp[int]{
+		{DiffRemove, 1}, {DiffAdd, 3}, {DiffRemove, 6}, {DiffAdd, 7}, {DiffAdd, 8},
+	}
+	if !slices.Equal(ops, want) {
+		t.Fatalf("DiffIter = %v, want %v", ops, want)
+	}
+	if it := old.DiffIter(old.Clone()); it.Valid() {
+		t.Fatalf("equal sets differ by %v", it.Op())
+	}
+}
+
+func TestDiff(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		a, b := NewSet(cmp.Compare[int]), NewSet(cmp.Compare[int])
+		inA, inB := map[int]bool{}, map[int]bool{}
+		randomOps(rng, a, inA, rng.Intn(100), 60)
+		randomOps(rng, b, inB, rng.Intn(100), 60)
+		added, removed := Diff(a, b)
+		for _, key := range added {
+			a.Insert(key)
+		}
+		for _, key := range removed {
+			a.Remove(key)
+		}
+		if !a.Equal(b) {
+			t.Fatalf("applying the diff gave %v, want %v", a.ToSlice(), b.ToSlice())
+		}
+		if !slices.IsSorted(added) || !slices.IsSorted(removed) {
+			t.Fatal("diff is out of order")
+		}
+	}
+	if added, removed := Diff(intSet(), intSet(1)); !slices.Equal(added, []int{1}) || removed != nil {
+		t.Fatalf("Diff from empty = %v, %v", added, removed)
+	}
+}
@@ -0,0 +1,225 @@
+package set
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoComparator is returned when decoding into a set that has no
+// comparator and whose element type has no natural order
+var ErrNoComparator = errors.New("set: no comparator for element type")
+
+// MarshalJSON encodes the set as a JSON array of its elements in order
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.keys())
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a
+// JSON array. The set's comparator is used to order them; a set without
+// one, such as the zero value, falls back to cmp.Compare for built-in
+// ordered element types.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	return s.decoded(items)
+}
+
+// MarshalBinary encodes the set as a uvarint element count followed by
+// the elements in ascending order as a single gob stream, so type
+// information is written once rather than per element
+func (s *Set[T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(binary.AppendUvarint(nil, uint64(s.size)))
+	enc := gob.NewEncoder(&buf)
+	if s.root != nil {
+		for node := s.minimum(s.root); node != nil; node = s.successor(node) {
+			if err := enc.Encode(node.key); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the contents of the set with data produced by
+// MarshalBinary. Elements arriving in order are bulk-built in linear time.
+func (s *Set[T]) UnmarshalBinary(data []byte) error {
+	r := bufio.NewReader(bytes.NewReader(data))
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("set: reading size header: %w", err)
+	}
+	// Every element takes at least one byte, which bounds a corrupt header
+	if n > uint64(len(data)) {
+		return fmt.Errorf("set: size header %d exceeds input length", n)
+	}
+	items := make([]T, n)
+	dec := gob.NewDecoder(r)
+	for i := range items {
+		if err := dec.Decode(&items[i]); err != nil {
+			return fmt.Errorf("set: decoding element %d: %w", i, err)
+		}
+	}
+	return s.decoded(items)
+}
+
+// GobEncode implements gob.GobEncoder using the binary format
+func (s *Set[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the binary format
+func (s *Set[T]) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+// MarshalText encodes the set as its elements in ascending order
+// separated by commas. Elements implementing encoding.TextMarshaler are
+// encoded with it, strings are used as they are and other elements are
+// printed with fmt. It fails if an encoded element contains a comma.
+func (s *Set[T]) MarshalText() ([]byte, error) {
+	var buf []byte
+	var err error
+	ascend(s.root, func(key T) bool {
+		start := len(buf)
+		if start > 0 {
+			buf = append(buf, ',')
+			start++
+		}
+		switch k := any(key).(type) {
+		case encoding.TextMarshaler:
+			var text []byte
+			if text, err = k.MarshalText(); err != nil {
+				return false
+			}
+			buf = append(buf, text...)
+		case string:
+			buf = append(buf, k...)
+		default:
+			buf = fmt.Append(buf, k)
+		}
+		if bytes.IndexByte(buf[start:], ',') >= 0 {
+			err = fmt.Errorf("set: element %q contains the delimiter", buf[start:])
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// UnmarshalText replaces the contents of the set with the comma-separated
+// elements of text, with surrounding spaces trimmed. Elements whose
+// pointer implements encoding.TextUnmarshaler are decoded with it, strings
+// are used as they are and other elements are scanned with fmt.
+func (s *Set[T]) UnmarshalText(text []byte) error {
+	var items []T
+	if len(bytes.TrimSpace(text)) > 0 {
+		for _, field := range strings.Split(string(text), ",") {
+			field = strings.TrimSpace(field)
+			var key T
+			var err error
+			switch k := any(&key).(type) {
+			case encoding.TextUnmarshaler:
+				err = k.UnmarshalText([]byte(field))
+			case *string:
+				*k = field
+			default:
+				_, err = fmt.Sscan(field, k)
+			}
+			if err != nil {
+				return fmt.Errorf("set: decoding element %q: %w", field, err)
+			}
+			items = append(items, key)
+		}
+	}
+	return s.decoded(items)
+}
+
+// decoded replaces the contents of s with decoded items in any order
+func (s *Set[T]) decoded(items []T) error {
+	if s.compare == nil {
+		s.compare = orderedCompare[T]()
+		if s.compare == nil {
+			return ErrNoComparator
+		}
+	}
+	s.checkMutable()
+	for i, item := range items {
+		items[i] = s.stored(s.canonical(item))
+	}
+	keys := items
+	if !s.ascending(keys) {
+		keys = sortUnique(keys, s.compare)
+	}
+	if s.maxSize > 0 && len(keys) > s.maxSize {
+		return fmt.Errorf("%w: %d elements", ErrFull, len(keys))
+	}
+	root := s.root
+	s.buildSorted(keys)
+	s.cleared(root)
+	for _, key := range keys {
+		s.changed(ChangeInsert, key)
+	}
+	return nil
+}
+
+// ascending reports whether keys are strictly ascending
+func (s *Set[T]) ascending(keys []T) bool {
+	for i := 1; i < len(keys); i++ {
+		if s.compare(keys[i-1], keys[i]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// orderedCompare returns cmp.Compare for built-in ordered types, or nil
+func orderedCompare[T any]() func(T, T) int {
+	var f any
+	switch any(*new(T)).(type) {
+	case int:
+		f = cmp.Compare[int]
+	case int8:
+		f = cmp.Compare[int8]
+	case int16:
+		f = cmp.Compare[int16]
+	case int32:
+		f = cmp.Compare[int32]
+	case int64:
+		f = cmp.Compare[int64]
+	case uint:
+		f = cmp.Compare[uint]
+	case uint8:
+		f = cmp.Compare[uint8]
+	case uint16:
+		f = cmp.Compare[uint16]
+	case uint32:
+		f = cmp.Compare[uint32]
+	case uint64:
+		f = cmp.Compare[uint64]
+	case uintptr:
+		f = cmp.Compare[uintptr]
+	case float32:
+		f = cmp.Compare[float32]
+	case float64:
+		f = cmp.Compare[float64]
+	case string:
+		f = cmp.Compare[string]
+	default:
+		return nil
+	}
+	return f.(func(T, T) int)
+}
@@ -0,0 +1,123 @@
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	s := intSet(3, 1, 2)
+	data, err := json.Marshal(s)
+	if err != nil || string(data) != "[1,2,3]" {
+		t.Fatalf("Marshal = %s, %v", data, err)
+	}
+	// Out of order input with duplicates is sorted and deduplicated
+	back := intSet(99)
+	if err := json.Unmarshal([]byte("[5,1,5,3]"), back); err != nil {
+		t.Fatal(err)
+	}
+	checkSet(t, back, map[int]bool{1: true, 3: true, 5: true})
+
+	// A zero set falls back to the natural order of its element type
+	var zero Set[string]
+	if err := json.Unmarshal([]byte(`["b","a"]`), &zero); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := json.Marshal(&zero); string(data) != `["a","b"]` {
+		t.Fatalf("zero set round trip = %s", data)
+	}
+	var points Set[struct{ X int }]
+	if err := json.Unmarshal([]byte(`[{"X":1}]`), &points); !errors.Is(err, ErrNoComparator) {
+		t.Fatalf("decoding without a comparator: %v", err)
+	}
+}
+
+func TestBinary(t *testing.T) {
+	s := NewSet(strings.Compare)
+	for _, w := range []string{"pear", "apple", "fig"} {
+		s.Insert(w)
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	back := NewSet(strings.Compare)
+	if err := back.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !back.Equal(s) || back.Validate() != nil {
+		t.Fatalf("binary round trip gave %v", back.ToSlice())
+	}
+	if err := back.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("truncated input was accepted")
+	}
+	if err := back.UnmarshalBinary([]byte{0xff, 0xff, 0x03}); err == nil {
+		t.Fatal("a corrupt size header was accepted")
+	}
+}
+
+func TestGob(t *testing.T) {
+	type record struct {
+		Name string
+		Tags *Set[int]
+	}
+	in := record{Name: "r", Tags: intSet(7, 3)}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	out := record{Tags: NewSet(cmp.Compare[int])}
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != "r" || !out.Tags.Equal(in.Tags) {
+		t.Fatalf("gob round trip gave %+v", out)
+	}
+}
+
+func TestText(t *testing.T) {
+	tags := NewSet(strings.Compare)
+	if err := tags.UnmarshalText([]byte(" prod , eu,prod,blue ")); err != nil {
+		t.Fatal(err)
+	}
+	text, err := tags.MarshalText()
+	if err != nil || string(text) != "blue,eu,prod" {
+		t.Fatalf("MarshalText = %q, %v", text, err)
+	}
+	tags.Insert("a,b")
+	if _, err := tags.MarshalText(); err == nil {
+		t.Fatal("an element holding the delimiter was encoded")
+	}
+	if err := tags.UnmarshalText(nil); err != nil || !tags.IsEmpty() {
+		t.Fatalf("empty text gave %v, %v", tags.ToSlice(), err)
+	}
+
+	nums := intSet()
+	if err := nums.UnmarshalText([]byte("3,1,2")); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := nums.MarshalText(); string(text) != "1,2,3" {
+		t.Fatalf("integers encode as %q", text)
+	}
+	if err := nums.UnmarshalText([]byte("1,x")); err == nil {
+		t.Fatal("a malformed integer was accepted")
+	}
+
+	// Elements implementing the text interfaces encode through them
+	addrs := NewSet(func(a, b netip.Addr) int { return a.Compare(b) })
+	if err := addrs.UnmarshalText([]byte("10.0.0.2, ::1, 10.0.0.1")); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := addrs.MarshalText(); string(text) != "10.0.0.1,10.0.0.2,::1" {
+		t.Fatalf("addresses encode as %q", text)
+	}
+	if err := addrs.UnmarshalText([]byte("10.0.0.300")); err == nil {
+		t.Fatal("a malformed address was accepted")
+	}
+}
@@ -0,0 +1,91 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrExists is returned by InsertE when an equal element is present
+var ErrExists = errors.New("set: element already exists")
+
+// ErrNotFound is returned by RemoveE and FindE when no equal element is
+// present
+var ErrNotFound = errors.New("set: element not found")
+
+// ErrBadComparator matches, with errors.Is, the errors of the E methods
+// caused by the comparator: a *ComparatorError, or a missing comparator
+var ErrBadComparator = errors.New("set: bad comparator")
+
+// InsertE is like Insert but reports failure as an error: ErrExists if an
+// equal element is present, ErrFull if the set is at its WithMaxSize
+// limit, ErrNilKey for a nil key the set rejects, and an error matching
+// ErrBadComparator for a zero value set or, under WithComparatorChecks, a
+// comparator that panicked or contradicted itself. ErrIterationScope is
+// returned instead of panicking as well.
+func (s *Set[T]) InsertE(key T) (err error) {
+	defer recovered(&err)
+	if err := s.usable(); err != nil {
+		return err
+	}
+	if !s.Insert(key) {
+		if s.maxSize > 0 && s.size >= s.maxSize && s.find(s.canonical(key)) == nil {
+			return fmt.Errorf("%w: %v", ErrFull, key)
+		}
+		return fmt.Errorf("%w: %v", ErrExists, key)
+	}
+	return nil
+}
+
+// RemoveE is like Remove but reports failure as an error, ErrNotFound if
+// no equal element is present and otherwise as InsertE does
+func (s *Set[T]) RemoveE(key T) (err error) {
+	defer recovered(&err)
+	if err := s.usable(); err != nil {
+		return err
+	}
+	if !s.Remove(key) {
+		return fmt.Errorf("%w: %v", ErrNotFound, key)
+	}
+	return nil
+}
+
+// FindE is like Find but reports failure as an error, ErrNotFound if no
+// equal element is present and otherwise as InsertE does
+func (s *Set[T]) FindE(key T) (found T, err error) {
+	defer recovered(&err)
+	if err := s.usable(); err != nil {
+		return found, err
+	}
+	found, ok := s.Find(key)
+	if !ok {
+		return found, fmt.Errorf("%w: %v", ErrNotFound, key)
+	}
+	return found, nil
+}
+
+// usable reports why s cannot compare keys, which only happens to a zero
+// value set
+func (s *Set[T]) usable() error {
+	if s.compare == nil {
+		return fmt.Errorf("%w: %w", ErrBadComparator, ErrNilComparator)
+	}
+	return nil
+}
+
+// recovered turns the panics the package raises for bad input into an
+// error stored in err and lets any other panic continue
+func recovered(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if ce, ok := r.(*ComparatorError); ok {
+		*err = ce
+		return
+	}
+	if e, ok := r.(error); ok && (e == ErrNilKey || e == ErrIterationScope) {
+		*err = e
+		return
+	}
+	panic(r)
+}
@@ -0,0 +1,65 @@
+package set
+
+import (
+	"cmp"
+	"errors"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	s := NewSetWithOptions(cmp.Compare[int], WithMaxSize[int](1))
+	if err := s.InsertE(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.InsertE(1); !errors.Is(err, ErrExists) {
+		t.Fatalf("InsertE of a present key: %v", err)
+	}
+	if err := s.InsertE(2); !errors.Is(err, ErrFull) {
+		t.Fatalf("InsertE into a full set: %v", err)
+	}
+	if err := s.RemoveE(2); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("RemoveE of a missing key: %v", err)
+	}
+	if _, err := s.FindE(2); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("FindE of a missing key: %v", err)
+	}
+	if found, err := s.FindE(1); err != nil || found != 1 {
+		t.Fatalf("FindE(1) = %d, %v", found, err)
+	}
+	if err := s.RemoveE(1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestErrorsInsteadOfPanics(t *testing.T) {
+	var zero Set[int]
+	if err := zero.InsertE(1); !errors.Is(err, ErrBadComparator) || !errors.Is(err, ErrNilComparator) {
+		t.Fatalf("InsertE into a zero set: %v", err)
+	}
+	s := intSet(1)
+	s.Locked(func(ReadOnlySet[int]) {
+		if err := s.InsertE(2); err != ErrIterationScope {
+			t.Fatalf("InsertE inside a locked scope: %v", err)
+		}
+	})
+	cause := errors.New("no order for 13")
+	picky := NewSetWithOptions(func(a, b int) int {
+		if a == 13 || b == 13 {
+			panic(cause)
+		}
+		return cmp.Compare(a, b)
+	}, WithComparatorChecks[int]())
+	picky.Insert(1)
+	if err := picky.InsertE(13); !errors.Is(err, ErrBadComparator) || !errors.Is(err, cause) {
+		t.Fatalf("InsertE with a panicking comparator: %v", err)
+	}
+	// Panics that do not come from bad input get through
+	boom := NewSet(func(a, b int) int { panic("boom") })
+	boom.Insert(1)
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Fatalf("recovered %v", r)
+		}
+	}()
+	boom.FindE(2)
+}
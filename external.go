@@ -0,0 +1,277 @@
+package set
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"sort"
+)
+
+// ExternalSet is a set whose elements don't all have to fit in memory.
+// Recent inserts are kept in an in-memory Set; once it reaches the memory
+// limit its contents are spilled to disk as a sorted run file. Lookups and
+// iteration merge the in-memory elements with all runs transparently.
+//
+// Elements removed after being spilled are remembered as in-memory
+// tombstones until Close.
+type ExternalSet[T any] struct {
+	compare    func(T, T) int
+	encode     func(T) ([]byte, error)
+	decode     func([]byte) (T, error)
+	dir        string
+	limit      int
+	mem        *Set[T]
+	tombstones *Set[T]
+	runs       []*run[T]
+	size       int
+}
+
+// run is a sorted, immutable file of spilled elements with a sparse index
+// of every runIndexStride-th key
+type run[T any] struct {
+	file  *os.File
+	size  int64
+	index []runIndexEntry[T]
+}
+
+type runIndexEntry[T any] struct {
+	key    T
+	offset int64
+}
+
+const runIndexStride = 64
+
+// NewExternalSet creates an external set ordering elements with compare.
+// At most limit elements are held in memory before spilling a run into
+// dir, using encode and decode to serialize elements.
+func NewExternalSet[T any](compare func(T, T) int, limit int, dir string,
+	encode func(T) ([]byte, error), decode func([]byte) (T, error)) *ExternalSet[T] {
+	requireComparator(compare)
+	if limit < 1 {
+		limit = 1
+	}
+	return &ExternalSet[T]{
+		compare:    compare,
+		encode:     encode,
+		decode:     decode,
+		dir:        dir,
+		limit:      limit,
+		mem:        NewSet(compare),
+		tombstones: NewSet(compare),
+	}
+}
+
+// Size returns the number of elements in the set
+func (e *ExternalSet[T]) Size() int {
+	return e.size
+}
+
+// Runs returns the number of runs spilled to disk
+func (e *ExternalSet[T]) Runs() int {
+	return len(e.runs)
+}
+
+// Insert adds a new element to the set, spilling the in-memory elements to
+// a new run when the memory limit is reached
+func (e *ExternalSet[T]) Insert(key T) (bool, error) {
+	if e.tombstones.Remove(key) {
+		e.size++
+		return true, nil
+	}
+	found, err := e.Contains(key)
+	if err != nil || found {
+		return false, err
+	}
+	e.mem.Insert(key)
+	e.size++
+	if e.mem.Size() >= e.limit {
+		return true, e.spill()
+	}
+	return true, nil
+}
+
+// Remove removes an element from the set
+func (e *ExternalSet[T]) Remove(key T) (bool, error) {
+	if e.mem.Remove(key) {
+		e.size--
+		return true, nil
+	}
+	found, err := e.Contains(key)
+	if err != nil || !found {
+		return false, err
+	}
+	e.tombstones.Insert(key)
+	e.size--
+	return true, nil
+}
+
+// Contains checks if an element exists in the set
+func (e *ExternalSet[T]) Contains(key T) (bool, error) {
+	if e.mem.Contains(key) {
+		return true, nil
+	}
+	if e.tombstones.Contains(key) {
+		return false, nil
+	}
+	for _, r := range e.runs {
+		found, err := e.runContains(r, key)
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+// Ascend calls fn for every element in ascending order until fn returns
+// false
+func (e *ExternalSet[T]) Ascend(fn func(key T) bool) error {
+	mem := e.mem.Begin()
+	readers := make([]*runReader[T], len(e.runs))
+	for i, r := range e.runs {
+		readers[i] = e.newRunReader(r, 0)
+		if err := readers[i].next(); err != nil {
+			return err
+		}
+	}
+	for {
+		var key T
+		from, have := -1, mem.Valid()
+		if have {
+			key = mem.Value()
+		}
+		for i, rr := range readers {
+			if rr.valid && (!have || e.compare(rr.key, key) < 0) {
+				key, from, have = rr.key, i, true
+			}
+		}
+		if !have {
+			return nil
+		}
+		if from == -1 {
+			mem.Next()
+		} else if err := readers[from].next(); err != nil {
+			return err
+		}
+		if e.tombstones.Contains(key) {
+			continue
+		}
+		if !fn(key) {
+			return nil
+		}
+	}
+}
+
+// Close removes all run files
+func (e *ExternalSet[T]) Close() error {
+	var first error
+	for _, r := range e.runs {
+		name := r.file.Name()
+		if err := r.file.Close(); err != nil && first == nil {
+			first = err
+		}
+		if err := os.Remove(name); err != nil && first == nil {
+			first = err
+		}
+	}
+	e.runs = nil
+	e.mem.Clear()
+	e.tombstones.Clear()
+	e.size = 0
+	return first
+}
+
+// spill writes the in-memory elements to a new run and clears them
+func (e *ExternalSet[T]) spill() error {
+	file, err := os.CreateTemp(e.dir, "set-run-*")
+	if err != nil {
+		return err
+	}
+	r := &run[T]{file: file}
+	w := bufio.NewWriter(file)
+	var lenBuf [binary.MaxVarintLen64]byte
+	i := 0
+	for it := e.mem.Begin(); it.Valid(); it.Next() {
+		data, err := e.encode(it.Value())
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+			return err
+		}
+		if i%runIndexStride == 0 {
+			r.index = append(r.index, runIndexEntry[T]{key: it.Value(), offset: r.size})
+		}
+		n := binary.PutUvarint(lenBuf[:], uint64(len(data)))
+		w.Write(lenBuf[:n])
+		w.Write(data)
+		r.size += int64(n + len(data))
+		i++
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	e.runs = append(e.runs, r)
+	e.mem.Clear()
+	return nil
+}
+
+func (e *ExternalSet[T]) runContains(r *run[T], key T) (bool, error) {
+	i := sort.Search(len(r.index), func(i int) bool {
+		return e.compare(r.index[i].key, key) > 0
+	})
+	if i == 0 {
+		return false, nil
+	}
+	rr := e.newRunReader(r, r.index[i-1].offset)
+	for n := 0; n < runIndexStride; n++ {
+		if err := rr.next(); err != nil || !rr.valid {
+			return false, err
+		}
+		cmp := e.compare(rr.key, key)
+		if cmp == 0 {
+			return true, nil
+		}
+		if cmp > 0 {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+// runReader decodes the elements of a run sequentially
+type runReader[T any] struct {
+	r     *bufio.Reader
+	dec   func([]byte) (T, error)
+	key   T
+	valid bool
+}
+
+func (e *ExternalSet[T]) newRunReader(r *run[T], offset int64) *runReader[T] {
+	return &runReader[T]{
+		r:   bufio.NewReader(io.NewSectionReader(r.file, offset, r.size-offset)),
+		dec: e.decode,
+	}
+}
+
+func (rr *runReader[T]) next() error {
+	n, err := binary.ReadUvarint(rr.r)
+	if err == io.EOF {
+		var zero T
+		rr.valid, rr.key = false, zero
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(rr.r, data); err != nil {
+		return err
+	}
+	if rr.key, err = rr.dec(data); err != nil {
+		return err
+	}
+	rr.valid = true
+	return nil
+}
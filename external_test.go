@@ -0,0 +1,95 @@
+package set
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+)
+
+func newIntExternalSet(t *testing.T, limit int) *ExternalSet[int] {
+	encode := func(v int) ([]byte, error) {
+		return binary.AppendVarint(nil, int64(v)), nil
+	}
+	decode := func(b []byte) (int, error) {
+		v, n := binary.Varint(b)
+		if n <= 0 {
+			return 0, errors.New("bad varint")
+		}
+		return int(v), nil
+	}
+	e := NewExternalSet(cmp.Compare[int], limit, t.TempDir(), encode, decode)
+	t.Cleanup(func() { e.Close() })
+	return e
+}
+
+func TestExternalSet(t *testing.T) {
+	e := newIntExternalSet(t, 100)
+	rng := rand.New(rand.NewSource(1))
+	want := map[int]bool{}
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(2000)
+		var ok bool
+		var err error
+		if rng.Intn(3) == 0 {
+			ok, err = e.Remove(key)
+			ok = ok == want[key]
+			delete(want, key)
+		} else {
+			ok, err = e.Insert(key)
+			ok = ok != want[key]
+			want[key] = true
+		}
+		if err != nil || !ok {
+			t.Fatalf("operation %d on %d: %v", i, key, err)
+		}
+	}
+	if e.Runs() == 0 {
+		t.Fatal("nothing was spilled")
+	}
+	if e.Size() != len(want) {
+		t.Fatalf("Size() = %d, want %d", e.Size(), len(want))
+	}
+	for key := 0; key < 2000; key++ {
+		if found, err := e.Contains(key); err != nil || found != want[key] {
+			t.Fatalf("Contains(%d) = %v, %v", key, found, err)
+		}
+	}
+	var got []int
+	if err := e.Ascend(func(key int) bool {
+		got = append(got, key)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var keys []int
+	for key := range want {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(got, keys) {
+		t.Fatalf("Ascend saw %d elements, want %d", len(got), len(keys))
+	}
+}
+
+func TestExternalSetClose(t *testing.T) {
+	e := newIntExternalSet(t, 2)
+	for i := 0; i < 10; i++ {
+		e.Insert(i)
+	}
+	var names []string
+	for _, r := range e.runs {
+		names = append(names, r.file.Name())
+	}
+	if err := e.Close(); err != nil || e.Size() != 0 || e.Runs() != 0 {
+		t.Fatalf("Close: %v", err)
+	}
+	for _, name := range names {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Fatalf("run %s was left behind", name)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package set
+
+// Fork returns a copy-on-write child of s for speculative changes. The
+// child is a full Set sharing the tree of s the way a snapshot does, so
+// forking takes O(1) time and a write to either set copies only the nodes
+// on its path. Both sets stay writable and independent of each other. The
+// child keeps the comparator, key cloning, normalization and nil key
+// policy of s, but none of its limits, subscribers or callbacks.
+//
+// A fork is discarded by dropping it, or applied to s with Commit.
+func (s *Set[T]) Fork() *Set[T] {
+	child := s.emptyCopy()
+	child.root = s.root
+	child.size = s.size
+	s.share(child)
+	return child
+}
+
+// Commit makes s hold the elements of fork, a child created by s.Fork. The
+// difference between the two is applied through Remove and Insert, so
+// subscribers, callbacks and limits of s see every change, and elements s
+// gained or lost after forking are reverted. Under WithMaxSize inserts
+// that do not fit are dropped. Both sets are walked once in order. The
+// fork stays usable afterwards and can be committed again.
+func (s *Set[T]) Commit(fork *Set[T]) {
+	s.checkMutable()
+	added, removed := Diff(s, fork)
+	for _, key := range removed {
+		s.Remove(key)
+	}
+	for _, key := range added {
+		s.Insert(key)
+	}
+}
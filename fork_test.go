@@ -0,0 +1,64 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestFork(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var changes int
+	count := func(int) { changes++ }
+	s := NewSetWithOptions(cmp.Compare[int], WithOnInsert(count), WithOnRemove(count))
+	want := map[int]bool{}
+	randomOps(rng, s, want, 500, 300)
+	parent := s.ToSlice()
+
+	child := s.Fork()
+	childWant := map[int]bool{}
+	for key := range want {
+		childWant[key] = true
+	}
+	randomOps(rng, child, childWant, 500, 300)
+	checkSet(t, child, childWant)
+	if !slices.Equal(s.ToSlice(), parent) {
+		t.Fatal("writes to the fork reached the parent")
+	}
+
+	// The parent stays writable without touching the fork
+	s.Insert(1000)
+	if child.Contains(1000) {
+		t.Fatal("a write to the parent reached the fork")
+	}
+	checkSet(t, child, childWant)
+
+	// Commit goes through Insert and Remove, firing the callbacks of the
+	// parent once per difference, including the revert of 1000
+	added, removed := Diff(s, child)
+	changes = 0
+	s.Commit(child)
+	checkSet(t, s, childWant)
+	if changes != len(added)+len(removed) || !slices.Contains(removed, 1000) {
+		t.Fatalf("Commit fired %d callbacks for %d differences", changes, len(added)+len(removed))
+	}
+
+	// The fork can be changed and committed again
+	child.Remove(child.ToSlice()[0])
+	s.Commit(child)
+	if !slices.Equal(s.ToSlice(), child.ToSlice()) {
+		t.Fatal("a second Commit did not apply")
+	}
+}
+
+func TestForkDiscard(t *testing.T) {
+	s := intSet(1, 2, 3)
+	child := s.Fork()
+	child.Clear()
+	child.Insert(9)
+	checkSet(t, s, map[int]bool{1: true, 2: true, 3: true})
+	if child.Size() != 1 || !child.Contains(9) {
+		t.Fatal("the fork lost its own changes")
+	}
+}
@@ -0,0 +1,47 @@
+package set
+
+import "fmt"
+
+// formatLimit is the number of elements printed by %v before the rest is
+// summarized
+const formatLimit = 64
+
+// String returns the elements in ascending order in braces, such as
+// "{1 2 3}", truncated after the first 64 elements
+func (s *Set[T]) String() string {
+	return fmt.Sprintf("%v", s)
+}
+
+// Format implements fmt.Formatter. The verb and flags are applied to every
+// element, which are printed in ascending order in braces. Under %v and
+// other verbs only the first 64 elements are printed, followed by the
+// number left out; %+v prints them all, and %#v draws the tree instead as
+// DumpTree does.
+func (s *Set[T]) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		s.DumpTree(f)
+		return
+	}
+	directive := fmt.FormatString(f, verb)
+	limit := formatLimit
+	if f.Flag('+') {
+		limit = s.size
+	}
+	fmt.Fprint(f, "{")
+	i := 0
+	ascend(s.root, func(key T) bool {
+		if i == limit {
+			return false
+		}
+		if i > 0 {
+			fmt.Fprint(f, " ")
+		}
+		fmt.Fprintf(f, directive, key)
+		i++
+		return true
+	})
+	if i < s.size {
+		fmt.Fprintf(f, " ... +%d more", s.size-i)
+	}
+	fmt.Fprint(f, "}")
+}
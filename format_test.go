@@ -0,0 +1,44 @@
+package set
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	if got := intSet().String(); got != "{}" {
+		t.Fatalf("empty set prints %q", got)
+	}
+	if got := intSet(3, 1, 2).String(); got != "{1 2 3}" {
+		t.Fatalf("String = %q", got)
+	}
+	if got := fmt.Sprintf("%03d", intSet(7, 12)); got != "{007 012}" {
+		t.Fatalf("%%03d prints %q", got)
+	}
+}
+
+func TestFormatLimit(t *testing.T) {
+	s := intSet()
+	for i := 0; i < 100; i++ {
+		s.Insert(i)
+	}
+	short := fmt.Sprint(s)
+	if !strings.HasSuffix(short, " 63 ... +36 more}") {
+		t.Fatalf("%%v of 100 elements prints %q", short)
+	}
+	full := fmt.Sprintf("%+v", s)
+	if strings.Contains(full, "more") || !strings.HasSuffix(full, " 98 99}") {
+		t.Fatalf("%%+v of 100 elements prints %q", full)
+	}
+}
+
+func TestFormatTree(t *testing.T) {
+	s := intSet(1, 2, 3, 4, 5)
+	var want bytes.Buffer
+	s.DumpTree(&want)
+	if got := fmt.Sprintf("%#v", s); got != want.String() {
+		t.Fatalf("%%#v prints\n%s\nwant\n%s", got, want.String())
+	}
+}
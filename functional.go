@@ -0,0 +1,84 @@
+package set
+
+// Filter returns a new set holding the elements for which pred returns
+// true. The elements come out of the walk in order, so the result is
+// bulk-built in linear time.
+func (s *Set[T]) Filter(pred func(key T) bool) *Set[T] {
+	result := s.emptyCopy()
+	var keys []T
+	ascend(s.root, func(key T) bool {
+		if pred(key) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	result.buildSorted(keys)
+	return result
+}
+
+// Partition returns two new sets holding the elements for which pred
+// returns true and false respectively
+func (s *Set[T]) Partition(pred func(key T) bool) (*Set[T], *Set[T]) {
+	in, out := s.emptyCopy(), s.emptyCopy()
+	var inKeys, outKeys []T
+	ascend(s.root, func(key T) bool {
+		if pred(key) {
+			inKeys = append(inKeys, key)
+		} else {
+			outKeys = append(outKeys, key)
+		}
+		return true
+	})
+	in.buildSorted(inKeys)
+	out.buildSorted(outKeys)
+	return in, out
+}
+
+// MapTo returns a new set ordered by compare holding fn applied to every
+// element of s. Of several elements mapping to equal values only the one
+// from the smallest element is kept. If fn preserves order under the two
+// comparators the mapped values are already sorted and the result is
+// built in linear time.
+func MapTo[T, U any](s *Set[T], fn func(T) U, compare func(U, U) int) *Set[U] {
+	result := NewSet(compare)
+	keys := make([]U, 0, s.size)
+	ascend(s.root, func(key T) bool {
+		keys = append(keys, fn(key))
+		return true
+	})
+	if !result.ascending(keys) {
+		keys = sortUnique(keys, compare)
+	}
+	result.buildSorted(keys)
+	return result
+}
+
+// Any returns true if pred returns true for some element, stopping at the
+// first one
+func (s *Set[T]) Any(pred func(key T) bool) bool {
+	return !ascend(s.root, func(key T) bool {
+		return !pred(key)
+	})
+}
+
+// All returns true if pred returns true for every element, stopping at the
+// first one for which it does not. It is true for an empty set.
+func (s *Set[T]) All(pred func(key T) bool) bool {
+	return ascend(s.root, pred)
+}
+
+// None returns true if pred returns false for every element
+func (s *Set[T]) None(pred func(key T) bool) bool {
+	return !s.Any(pred)
+}
+
+// Reduce folds the elements of s in ascending order into an accumulator
+// starting from init
+func Reduce[T, A any](s *Set[T], init A, fn func(acc A, key T) A) A {
+	acc := init
+	ascend(s.root, func(key T) bool {
+		acc = fn(acc, key)
+		return true
+	})
+	return acc
+}
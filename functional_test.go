@@ -0,0 +1,58 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestFilterPartition(t *testing.T) {
+	s := intSet(1, 2, 3, 4, 5, 6)
+	even := func(key int) bool { return key%2 == 0 }
+	evens := s.Filter(even)
+	checkSet(t, evens, map[int]bool{2: true, 4: true, 6: true})
+	in, out := s.Partition(even)
+	checkSet(t, in, map[int]bool{2: true, 4: true, 6: true})
+	checkSet(t, out, map[int]bool{1: true, 3: true, 5: true})
+	evens.Insert(8)
+	if s.Contains(8) || s.Size() != 6 {
+		t.Fatal("the filtered set shares the tree")
+	}
+}
+
+func TestMapTo(t *testing.T) {
+	s := intSet(-2, -1, 0, 1, 2)
+	squares := MapTo(s, func(key int) int { return key * key }, cmp.Compare[int])
+	checkSet(t, squares, map[int]bool{0: true, 1: true, 4: true})
+	halves := MapTo(s, func(key int) float64 { return float64(key) / 2 }, cmp.Compare[float64])
+	if got := halves.ToSlice(); !slices.Equal(got, []float64{-1, -0.5, 0, 0.5, 1}) || halves.Validate() != nil {
+		t.Fatalf("order-preserving MapTo = %v", got)
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	s := intSet(1, 2, 3, 4)
+	visits := 0
+	positive := func(key int) bool {
+		visits++
+		return key > 0
+	}
+	if !s.All(positive) || visits != 4 {
+		t.Fatal("All is wrong")
+	}
+	visits = 0
+	if !s.Any(positive) || visits != 1 {
+		t.Fatalf("Any took %d visits to find the first match", visits)
+	}
+	big := func(key int) bool { return key > 3 }
+	if s.All(big) || !s.Any(big) || s.None(big) || !s.None(func(key int) bool { return key > 4 }) {
+		t.Fatal("a predicate is wrong")
+	}
+	empty := intSet()
+	if !empty.All(big) || empty.Any(positive) || !empty.None(positive) {
+		t.Fatal("predicates over an empty set are wrong")
+	}
+	if got := Reduce(s, "", func(acc string, key int) string { return acc + string(rune('0'+key)) }); got != "1234" {
+		t.Fatalf("Reduce = %q", got)
+	}
+}
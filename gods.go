@@ -0,0 +1,166 @@
+package set
+
+// GodsSet offers the method set of the tree set of
+// github.com/emirpasic/gods on top of a Set, satisfying its Container,
+// Set, EnumerableWithIndex and JSON interfaces for the element type, so it
+// can be passed to code written against that package. Indexes passed to
+// the enumeration callbacks are the positions in ascending order.
+type GodsSet[T any] struct {
+	set *Set[T]
+}
+
+// NewGodsSet creates a new set with a custom comparator holding values
+func NewGodsSet[T any](compare func(T, T) int, values ...T) *GodsSet[T] {
+	g := &GodsSet[T]{set: NewSet(compare)}
+	g.Add(values...)
+	return g
+}
+
+// Gods returns a GodsSet backed by s, so changes through either are seen
+// by both
+func (s *Set[T]) Gods() *GodsSet[T] {
+	return &GodsSet[T]{set: s}
+}
+
+// Set returns the Set backing g
+func (g *GodsSet[T]) Set() *Set[T] {
+	return g.set
+}
+
+// Empty returns true if the set has no elements
+func (g *GodsSet[T]) Empty() bool {
+	return g.set.IsEmpty()
+}
+
+// Size returns the number of elements in the set
+func (g *GodsSet[T]) Size() int {
+	return g.set.Size()
+}
+
+// Clear removes all elements from the set
+func (g *GodsSet[T]) Clear() {
+	g.set.Clear()
+}
+
+// Values returns the elements in ascending order
+func (g *GodsSet[T]) Values() []T {
+	return g.set.ToSlice()
+}
+
+// String returns the elements in ascending order, formatted as by Set
+func (g *GodsSet[T]) String() string {
+	return g.set.String()
+}
+
+// Add inserts the given elements
+func (g *GodsSet[T]) Add(elements ...T) {
+	g.set.AddAll(elements...)
+}
+
+// Remove removes the given elements
+func (g *GodsSet[T]) Remove(elements ...T) {
+	g.set.RemoveAll(elements...)
+}
+
+// Contains checks if all given elements are in the set. It is true when
+// no elements are given.
+func (g *GodsSet[T]) Contains(elements ...T) bool {
+	return g.set.ContainsAll(elements...)
+}
+
+// Intersection returns a new set holding the elements present in both
+// sets
+func (g *GodsSet[T]) Intersection(another *GodsSet[T]) *GodsSet[T] {
+	return g.combined(IntersectIter(g.set, another.set))
+}
+
+// Union returns a new set holding the elements of either set
+func (g *GodsSet[T]) Union(another *GodsSet[T]) *GodsSet[T] {
+	return g.combined(UnionIter(g.set, another.set))
+}
+
+// Difference returns a new set holding the elements of g that are not in
+// another
+func (g *GodsSet[T]) Difference(another *GodsSet[T]) *GodsSet[T] {
+	return g.combined(DifferenceIter(g.set, another.set))
+}
+
+// Each calls f for every element in ascending order
+func (g *GodsSet[T]) Each(f func(index int, value T)) {
+	i := 0
+	g.set.Ascend(func(key T) bool {
+		f(i, key)
+		i++
+		return true
+	})
+}
+
+// Map returns a new set, ordered like g, holding f applied to every
+// element
+func (g *GodsSet[T]) Map(f func(index int, value T) T) *GodsSet[T] {
+	keys := make([]T, 0, g.set.Size())
+	g.Each(func(index int, value T) {
+		keys = append(keys, f(index, value))
+	})
+	result := g.set.emptyCopy()
+	result.buildSorted(sortUnique(keys, result.compare))
+	return &GodsSet[T]{set: result}
+}
+
+// Select returns a new set holding the elements for which f returns true
+func (g *GodsSet[T]) Select(f func(index int, value T) bool) *GodsSet[T] {
+	i := -1
+	return &GodsSet[T]{set: g.set.Filter(func(key T) bool {
+		i++
+		return f(i, key)
+	})}
+}
+
+// Any returns true if f returns true for some element
+func (g *GodsSet[T]) Any(f func(index int, value T) bool) bool {
+	index, _ := g.Find(f)
+	return index >= 0
+}
+
+// All returns true if f returns true for every element
+func (g *GodsSet[T]) All(f func(index int, value T) bool) bool {
+	index, _ := g.Find(func(index int, value T) bool {
+		return !f(index, value)
+	})
+	return index < 0
+}
+
+// Find returns the index and value of the first element for which f
+// returns true, or -1 and the zero value if there is none
+func (g *GodsSet[T]) Find(f func(index int, value T) bool) (int, T) {
+	i, found := 0, -1
+	var value T
+	g.set.Ascend(func(key T) bool {
+		if f(i, key) {
+			found, value = i, key
+			return false
+		}
+		i++
+		return true
+	})
+	return found, value
+}
+
+// ToJSON encodes the set as a JSON array of its elements in order
+func (g *GodsSet[T]) ToJSON() ([]byte, error) {
+	return g.set.MarshalJSON()
+}
+
+// FromJSON replaces the contents of the set with the elements of a JSON
+// array
+func (g *GodsSet[T]) FromJSON(data []byte) error {
+	return g.set.UnmarshalJSON(data)
+}
+
+// combined collects the elements of it, which come out in ascending order,
+// into a new set ordered like g
+func (g *GodsSet[T]) combined(it *CombineIterator[T]) *GodsSet[T] {
+	result := g.set.emptyCopy()
+	result.buildSorted(it.ToSlice())
+	return &GodsSet[T]{set: result}
+}
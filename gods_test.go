@@ -0,0 +1,122 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+// The interfaces of github.com/emirpasic/gods/v2 that GodsSet satisfies
+type (
+	godsContainer[T any] interface {
+		Empty() bool
+		Size() int
+		Clear()
+		Values() []T
+		String() string
+	}
+	godsSet[T any] interface {
+		Add(elements ...T)
+		Remove(elements ...T)
+		Contains(elements ...T) bool
+		godsContainer[T]
+	}
+	godsEnumerableWithIndex[T any] interface {
+		Each(func(index int, value T))
+		Any(func(index int, value T) bool) bool
+		All(func(index int, value T) bool) bool
+		Find(func(index int, value T) bool) (int, T)
+	}
+	godsJSON interface {
+		ToJSON() ([]byte, error)
+		FromJSON([]byte) error
+	}
+)
+
+var (
+	_ godsSet[int]                 = (*GodsSet[int])(nil)
+	_ godsEnumerableWithIndex[int] = (*GodsSet[int])(nil)
+	_ godsJSON                     = (*GodsSet[int])(nil)
+)
+
+func TestGodsSet(t *testing.T) {
+	g := NewGodsSet(cmp.Compare[int], 5, 1, 3)
+	g.Add(2, 4, 3)
+	if g.Size() != 5 || g.Empty() || !g.Contains(1, 5) || g.Contains(1, 6) || !g.Contains() {
+		t.Fatalf("set holds %v", g.Values())
+	}
+	g.Remove(1, 9)
+	if got, want := g.Values(), []int{2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("Values() = %v, want %v", got, want)
+	}
+	if g.String() != "{2 3 4 5}" {
+		t.Fatalf("String() = %q", g.String())
+	}
+	g.Clear()
+	if !g.Empty() || g.Size() != 0 {
+		t.Fatal("Clear left elements")
+	}
+}
+
+func TestGodsEnumerable(t *testing.T) {
+	g := NewGodsSet(cmp.Compare[int], 10, 20, 30, 40)
+	var indexes []int
+	g.Each(func(index int, value int) {
+		if value != 10*(index+1) {
+			t.Fatalf("Each passed %d at index %d", value, index)
+		}
+		indexes = append(indexes, index)
+	})
+	if !slices.Equal(indexes, []int{0, 1, 2, 3}) {
+		t.Fatalf("Each visited indexes %v", indexes)
+	}
+	if index, value := g.Find(func(_ int, value int) bool { return value > 15 }); index != 1 || value != 20 {
+		t.Fatalf("Find = %d, %d", index, value)
+	}
+	if index, _ := g.Find(func(_ int, value int) bool { return value > 99 }); index != -1 {
+		t.Fatalf("Find of nothing = %d", index)
+	}
+	if !g.Any(func(_ int, value int) bool { return value == 30 }) || g.All(func(_ int, value int) bool { return value < 40 }) {
+		t.Fatal("Any or All gave the wrong answer")
+	}
+	folded := g.Map(func(_ int, value int) int { return value % 20 })
+	if got := folded.Values(); !slices.Equal(got, []int{0, 10}) {
+		t.Fatalf("Map = %v", got)
+	}
+	odd := g.Select(func(index int, _ int) bool { return index%2 == 1 })
+	if got := odd.Values(); !slices.Equal(got, []int{20, 40}) {
+		t.Fatalf("Select = %v", got)
+	}
+}
+
+func TestGodsAlgebra(t *testing.T) {
+	a := NewGodsSet(cmp.Compare[int], 1, 2, 3, 4)
+	b := NewGodsSet(cmp.Compare[int], 3, 4, 5)
+	if got := a.Union(b).Values(); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("Union = %v", got)
+	}
+	if got := a.Intersection(b).Values(); !slices.Equal(got, []int{3, 4}) {
+		t.Fatalf("Intersection = %v", got)
+	}
+	if got := a.Difference(b).Values(); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("Difference = %v", got)
+	}
+}
+
+func TestGodsJSON(t *testing.T) {
+	g := NewGodsSet(cmp.Compare[int], 3, 1, 2)
+	data, err := g.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[1,2,3]" {
+		t.Fatalf("ToJSON() = %s", data)
+	}
+	h := NewGodsSet(cmp.Compare[int], 9)
+	if err := h.FromJSON([]byte("[5,4]")); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Values(); !slices.Equal(got, []int{4, 5}) {
+		t.Fatalf("FromJSON gave %v", got)
+	}
+}
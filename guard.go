@@ -0,0 +1,53 @@
+package set
+
+import "errors"
+
+// ErrIterationScope is the panic value raised when a set is mutated while
+// one of its iteration scopes is open
+var ErrIterationScope = errors.New("set: mutation inside an iteration scope")
+
+// ErrConcurrentModification is the panic value raised when an iterator is
+// used after its set was modified other than through the iterator itself
+var ErrConcurrentModification = errors.New("set: set modified during iteration")
+
+// WithSafeIterators makes iterators follow modifications of the set that
+// were not made through them, instead of panicking with
+// ErrConcurrentModification. An iterator whose element is still present
+// stays on it. One whose element was removed behaves as if the removal had
+// been made through the iterator: it moves to the following element, and
+// a call to Next or Prev only takes it to the element following or
+// preceding the removed one. Bookmarks are not followed. Safe iterators
+// remember their element at every step, which costs a copy of the key.
+func WithSafeIterators[T any]() Option[T] {
+	return func(s *Set[T]) {
+		s.safeIters = true
+	}
+}
+
+// ReadOnlySet is the read-only view of a set handed to Locked callbacks
+type ReadOnlySet[T any] interface {
+	Size() int
+	IsEmpty() bool
+	Contains(key T) bool
+	Begin() *Iterator[T]
+	End() *Iterator[T]
+	RBegin() *Iterator[T]
+	REnd() *Iterator[T]
+}
+
+// Locked opens an iteration scope for the duration of fn. Any Insert,
+// Remove or Clear on the set while a scope is open panics with
+// ErrIterationScope instead of silently invalidating the iteration.
+func (s *Set[T]) Locked(fn func(ro ReadOnlySet[T])) {
+	s.scopes++
+	defer func() { s.scopes-- }()
+	fn(s)
+}
+
+// checkMutable prepares s for a mutation, panicking inside an iteration
+// scope
+func (s *Set[T]) checkMutable() {
+	if s.scopes > 0 {
+		panic(ErrIterationScope)
+	}
+}
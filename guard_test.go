@@ -0,0 +1,42 @@
+package set
+
+import "testing"
+
+func mustPanicWith(t *testing.T, want error, fn func()) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != want {
+			t.Fatalf("recovered %v, want %v", r, want)
+		}
+	}()
+	fn()
+}
+
+func TestLocked(t *testing.T) {
+	s := intSet(1, 2, 3)
+	s.Locked(func(ro ReadOnlySet[int]) {
+		if ro.Size() != 3 || !ro.Contains(2) || ro.Begin().Value() != 1 || ro.RBegin().Value() != 3 {
+			t.Fatal("reads inside a scope are wrong")
+		}
+		for _, mutate := range []func(){
+			func() { s.Insert(4) },
+			func() { s.Remove(1) },
+			func() { s.Clear() },
+		} {
+			mustPanicWith(t, ErrIterationScope, mutate)
+		}
+		// Scopes nest; the set stays locked until the outer one closes
+		s.Locked(func(ReadOnlySet[int]) {})
+		mustPanicWith(t, ErrIterationScope, func() { s.Insert(4) })
+	})
+	checkSet(t, s, map[int]bool{1: true, 2: true, 3: true})
+
+	// A panicking callback still closes its scope
+	func() {
+		defer func() { recover() }()
+		s.Locked(func(ReadOnlySet[int]) { panic("callback") })
+	}()
+	if !s.Insert(4) {
+		t.Fatal("Insert failed after the scope closed")
+	}
+}
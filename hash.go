@@ -0,0 +1,27 @@
+package set
+
+// Hash returns a fingerprint of the elements of s computed from h, which
+// hashes a single element. The fingerprint does not depend on the order of
+// the elements, so sets holding the same elements hash equally even under
+// different comparators. Each element hash is scrambled before the hashes
+// are summed, so related element hashes, such as consecutive integers, do
+// not cancel out. It takes O(n) time.
+func (s *Set[T]) Hash(h func(key T) uint64) uint64 {
+	sum := mix64(uint64(s.size))
+	ascend(s.root, func(key T) bool {
+		sum += mix64(h(key))
+		return true
+	})
+	return sum
+}
+
+// mix64 is the finalizer of SplitMix64, a bijection that spreads every
+// input bit over the whole output
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
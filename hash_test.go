@@ -0,0 +1,35 @@
+package set
+
+import (
+	"cmp"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	h := func(key int) uint64 { return uint64(key) }
+	a := intSet(1, 2, 3)
+	// The same elements under the reverse order hash equally
+	b := NewSet(func(x, y int) int { return cmp.Compare(y, x) })
+	for _, key := range []int{3, 1, 2} {
+		b.Insert(key)
+	}
+	if a.Hash(h) != b.Hash(h) {
+		t.Fatal("equal sets hash differently")
+	}
+	// Sums of consecutive integers collide unless the hashes are scrambled
+	if a.Hash(h) == intSet(0, 2, 4).Hash(h) {
+		t.Fatal("{1 2 3} and {0 2 4} hash equally")
+	}
+	before := a.Hash(h)
+	a.Insert(4)
+	if a.Hash(h) == before {
+		t.Fatal("an insert left the hash unchanged")
+	}
+	a.Remove(4)
+	if a.Hash(h) != before {
+		t.Fatal("undoing an insert changed the hash")
+	}
+	if intSet().Hash(h) == intSet(0).Hash(h) {
+		t.Fatal("{} and {0} hash equally")
+	}
+}
@@ -0,0 +1,18 @@
+package set
+
+// Histogram counts the elements falling into the buckets delimited by the
+// ascending bounds. The result has len(bounds)+1 entries: the elements less
+// than bounds[0], then those in [bounds[i-1], bounds[i]) for each i, and
+// finally those not less than the last bound. Each bucket costs one rank
+// query, so the total is O(len(bounds)·log n).
+func (s *Set[T]) Histogram(bounds []T) []int {
+	counts := make([]int, len(bounds)+1)
+	prev := 0
+	for i, bound := range bounds {
+		rank := s.rank(s.canonical(bound))
+		counts[i] = rank - prev
+		prev = rank
+	}
+	counts[len(bounds)] = s.size - prev
+	return counts
+}
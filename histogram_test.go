@@ -0,0 +1,19 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	s := intSet(1, 2, 5, 7, 10, 11, 20)
+	if got, want := s.Histogram([]int{5, 10, 15}), []int{2, 2, 2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("Histogram = %v, want %v", got, want)
+	}
+	if got, want := s.Histogram(nil), []int{7}; !slices.Equal(got, want) {
+		t.Fatalf("Histogram without bounds = %v, want %v", got, want)
+	}
+	if got, want := intSet().Histogram([]int{0}), []int{0, 0}; !slices.Equal(got, want) {
+		t.Fatalf("Histogram of an empty set = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package set
+
+// Integer is the constraint satisfied by all integer types
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Mex returns the smallest integer not less than min that is not in the
+// set. It can serve as a free ID allocator. Since elements are distinct,
+// the elements from min onwards are consecutive exactly up to the answer,
+// which is found by binary search over ranks without visiting the run.
+// It returns false if every integer from min up to the largest value of T
+// is in the set, so there is no answer.
+func Mex[T Integer](s *Set[T], min T) (T, bool) {
+	first := s.rank(min)
+	lo, hi := 0, s.size-first
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if s.at(first+mid).key == min+T(mid) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	// A run ending at the largest value of T would wrap around
+	if last := min + T(lo-1); lo > 0 && last+1 < last {
+		return 0, false
+	}
+	return min + T(lo), true
+}
+
+// ComplementIterator walks the integers of an interval that are not in a set
+type ComplementIterator[T Integer] struct {
+	set   *Set[T]
+	node  *Node[T]
+	value T
+	hi    T
+}
+
+// Complement returns a lazy iterator over the integers in [lo, hi) that are
+// not in the set. The set must not be modified while the iterator is in
+// use.
+func Complement[T Integer](s *Set[T], lo, hi T) *ComplementIterator[T] {
+	it := &ComplementIterator[T]{
+		set:   s,
+		node:  s.lowerBound(lo),
+		value: lo,
+		hi:    hi,
+	}
+	it.skip()
+	return it
+}
+
+// Valid returns true if the iterator is positioned on a value
+func (it *ComplementIterator[T]) Valid() bool {
+	return it.value < it.hi
+}
+
+// Value returns the current value
+func (it *ComplementIterator[T]) Value() T {
+	return it.value
+}
+
+// Next moves to the next absent value
+func (it *ComplementIterator[T]) Next() bool {
+	if !it.Valid() {
+		return false
+	}
+	it.value++
+	it.skip()
+	return it.Valid()
+}
+
+// skip advances past the values present in the set
+func (it *ComplementIterator[T]) skip() {
+	for it.node != nil && it.value < it.hi {
+		key := it.node.key
+		if key > it.value {
+			return
+		}
+		if key == it.value {
+			it.value++
+		}
+		it.node = it.set.successor(it.node)
+	}
+}
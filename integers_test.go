@@ -0,0 +1,76 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestMex(t *testing.T) {
+	s := intSet(0, 1, 2, 4, 5, 9)
+	for _, c := range []struct{ min, want int }{{0, 3}, {3, 3}, {4, 6}, {9, 10}, {-5, -5}} {
+		if got, ok := Mex(s, c.min); !ok || got != c.want {
+			t.Errorf("Mex(%d) = %d, %v, want %d", c.min, got, ok, c.want)
+		}
+	}
+}
+
+func TestMexAtTypeMaximum(t *testing.T) {
+	s := NewSet(cmp.Compare[uint8])
+	for i := 0; i < 256; i++ {
+		s.Insert(uint8(i))
+	}
+	if got, ok := Mex(s, 200); ok {
+		t.Fatalf("Mex of a full range = %d, want none", got)
+	}
+	s.Remove(255)
+	if got, ok := Mex(s, 3); !ok || got != 255 {
+		t.Fatalf("Mex(3) = %d, %v, want 255", got, ok)
+	}
+	signed := NewSet(cmp.Compare[int8])
+	signed.Insert(126)
+	signed.Insert(127)
+	if got, ok := Mex(signed, 126); ok {
+		t.Fatalf("Mex of a run up to 127 = %d, want none", got)
+	}
+}
+
+func TestComplement(t *testing.T) {
+	collect := func(it *ComplementIterator[int]) []int {
+		var got []int
+		for ; it.Valid(); it.Next() {
+			got = append(got, it.Value())
+		}
+		return got
+	}
+	s := intSet(-3, 0, 1, 2, 5, 9, 20)
+	if got, want := collect(Complement(s, 0, 10)), []int{3, 4, 6, 7, 8}; !slices.Equal(got, want) {
+		t.Fatalf("Complement(0, 10) = %v, want %v", got, want)
+	}
+	if got, want := collect(Complement(s, -4, 0)), []int{-4, -2, -1}; !slices.Equal(got, want) {
+		t.Fatalf("Complement(-4, 0) = %v, want %v", got, want)
+	}
+	if got := collect(Complement(s, 0, 3)); got != nil {
+		t.Fatalf("Complement of a covered interval = %v", got)
+	}
+	if got, want := collect(Complement(intSet(), 7, 9)), []int{7, 8}; !slices.Equal(got, want) {
+		t.Fatalf("Complement in an empty set = %v, want %v", got, want)
+	}
+	it := Complement(s, 5, 5)
+	if it.Valid() || it.Next() {
+		t.Fatal("an empty interval yielded a value")
+	}
+}
+
+func TestComplementAtTypeMaximum(t *testing.T) {
+	s := NewSet(cmp.Compare[uint8])
+	s.Insert(253)
+	it := Complement(s, 250, 255)
+	var got []uint8
+	for ; it.Valid(); it.Next() {
+		got = append(got, it.Value())
+	}
+	if want := []uint8{250, 251, 252, 254}; !slices.Equal(got, want) {
+		t.Fatalf("Complement(250, 255) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package set
+
+import "strings"
+
+// Interner returns a single canonical copy of equal strings, so callers
+// holding many duplicates can share one allocation. It is built on a Set
+// of the strings seen so far.
+type Interner struct {
+	set   *Set[string]
+	stats InternStats
+}
+
+// InternStats reports how effective an Interner has been
+type InternStats struct {
+	// Calls is the number of Intern calls
+	Calls int
+	// Hits is the number of calls that returned an existing string
+	Hits int
+	// BytesSaved is the total length of the strings that were replaced
+	// by an existing copy
+	BytesSaved int64
+}
+
+// HitRate returns the fraction of calls that returned an existing string
+func (st InternStats) HitRate() float64 {
+	if st.Calls == 0 {
+		return 0
+	}
+	return float64(st.Hits) / float64(st.Calls)
+}
+
+// NewInterner creates an empty interner
+func NewInterner() *Interner {
+	return &Interner{
+		set: NewSet(strings.Compare),
+	}
+}
+
+// Intern returns the canonical copy of s, storing s itself if no equal
+// string has been interned before
+func (in *Interner) Intern(s string) string {
+	in.stats.Calls++
+	node, inserted := in.set.insert(s)
+	if !inserted {
+		in.stats.Hits++
+		in.stats.BytesSaved += int64(len(s))
+	}
+	return node.key
+}
+
+// Len returns the number of distinct strings interned
+func (in *Interner) Len() int {
+	return in.set.Size()
+}
+
+// Stats returns the interner's statistics
+func (in *Interner) Stats() InternStats {
+	return in.stats
+}
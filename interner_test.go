@@ -0,0 +1,27 @@
+package set
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestInterner(t *testing.T) {
+	in := NewInterner()
+	first := in.Intern(strings.Repeat("ab", 3))
+	second := in.Intern(strings.Repeat("ab", 3))
+	if unsafe.StringData(first) != unsafe.StringData(second) {
+		t.Fatal("equal strings were not shared")
+	}
+	in.Intern("other")
+	st := in.Stats()
+	if in.Len() != 2 || st.Calls != 3 || st.Hits != 1 || st.BytesSaved != 6 {
+		t.Fatalf("Len() = %d, stats %+v", in.Len(), st)
+	}
+	if rate := st.HitRate(); rate != 1.0/3 {
+		t.Fatalf("HitRate() = %v", rate)
+	}
+	if (InternStats{}).HitRate() != 0 {
+		t.Fatal("HitRate of no calls is not 0")
+	}
+}
@@ -0,0 +1,85 @@
+package set
+
+import (
+	"iter"
+	"slices"
+)
+
+// AppendTo appends the elements in ascending order to dst and returns the
+// extended slice
+func (s *Set[T]) AppendTo(dst []T) []T {
+	dst = slices.Grow(dst, s.size)
+	ascend(s.root, func(key T) bool {
+		dst = append(dst, key)
+		return true
+	})
+	return dst
+}
+
+// Collect returns the elements in ascending order in a new slice, the
+// result of slices.Collect(s.Values()) without going through the iterator
+func (s *Set[T]) Collect() []T {
+	return s.AppendTo(nil)
+}
+
+// Values returns an iterator over the elements in ascending order, for
+// range loops and functions such as slices.Collect
+func (s *Set[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		ascend(s.root, yield)
+	}
+}
+
+// Backward returns an iterator over the elements in descending order
+func (s *Set[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		descend(s.root, yield)
+	}
+}
+
+// Indexed returns an iterator over the positions and elements in
+// ascending order
+func (s *Set[T]) Indexed() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		ascend(s.root, func(key T) bool {
+			ok := yield(i, key)
+			i++
+			return ok
+		})
+	}
+}
+
+// SortView presents a set as a sort.Interface over its elements in
+// ascending order, for code taking one, such as sort.IsSorted or a
+// binary search over indexes. Swap is a no-op, since the set keeps its
+// elements in order, so the view only suits code that reads through Len
+// and Less; sorting it with sort.Sort changes nothing.
+type SortView[T any] struct {
+	set *Set[T]
+}
+
+// SortView returns a sort.Interface view of s. Each Less call looks its
+// elements up by rank in O(log n) time.
+func (s *Set[T]) SortView() SortView[T] {
+	return SortView[T]{set: s}
+}
+
+// Len returns the number of elements
+func (v SortView[T]) Len() int {
+	return v.set.size
+}
+
+// Less reports whether the element at index i sorts before the one at j
+func (v SortView[T]) Less(i, j int) bool {
+	return v.set.compare(v.set.at(i).key, v.set.at(j).key) < 0
+}
+
+// Swap does nothing: the elements are always in order, so a correct sort
+// never needs to swap them
+func (v SortView[T]) Swap(i, j int) {}
+
+// At returns the element at index i
+func (v SortView[T]) At(i int) T {
+	return v.set.at(i).key
+}
@@ -0,0 +1,61 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestAppendToAndCollect(t *testing.T) {
+	s := intSet(3, 1, 2)
+	if got := s.AppendTo([]int{9}); !slices.Equal(got, []int{9, 1, 2, 3}) {
+		t.Fatalf("AppendTo = %v", got)
+	}
+	if got := s.Collect(); !slices.Equal(got, slices.Collect(s.Values())) {
+		t.Fatalf("Collect = %v", got)
+	}
+	if got := intSet().Collect(); got != nil {
+		t.Fatalf("Collect of an empty set = %v", got)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	s := intSet(1, 2, 3, 4)
+	var fwd, back []int
+	for key := range s.Values() {
+		if key == 3 {
+			break
+		}
+		fwd = append(fwd, key)
+	}
+	for key := range s.Backward() {
+		back = append(back, key)
+	}
+	if !slices.Equal(fwd, []int{1, 2}) || !slices.Equal(back, []int{4, 3, 2, 1}) {
+		t.Fatalf("Values = %v, Backward = %v", fwd, back)
+	}
+	for i, key := range s.Indexed() {
+		if key != i+1 {
+			t.Fatalf("Indexed yielded %d at %d", key, i)
+		}
+	}
+}
+
+func TestSortView(t *testing.T) {
+	s := NewSet(func(a, b int) int { return cmp.Compare(b, a) })
+	for _, key := range []int{5, 1, 9, 3} {
+		s.Insert(key)
+	}
+	v := s.SortView()
+	if v.Len() != 4 || !sort.IsSorted(v) || v.At(0) != 9 || !v.Less(1, 2) {
+		t.Fatal("the view does not follow the order of the set")
+	}
+	sort.Sort(sort.Reverse(v))
+	if !slices.Equal(s.ToSlice(), []int{9, 5, 3, 1}) {
+		t.Fatalf("sorting the view changed the set to %v", s.ToSlice())
+	}
+	if i := sort.Search(v.Len(), func(i int) bool { return v.At(i) <= 4 }); i != 2 {
+		t.Fatalf("binary search for 4 stopped at %d", i)
+	}
+}
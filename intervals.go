@@ -0,0 +1,110 @@
+package set
+
+// Interval is the half-open interval [Lo, Hi)
+type Interval[T any] struct {
+	Lo, Hi T
+}
+
+// IntervalSet is a set of half-open intervals supporting stabbing and
+// overlap queries. Intervals are kept in a red-black tree ordered by their
+// bounds, and every node also records the largest upper bound in its
+// subtree, so queries skip every subtree that ends before the query range.
+type IntervalSet[T any] struct {
+	tree    *Set[*intervalEntry[T]]
+	compare func(T, T) int
+}
+
+type intervalEntry[T any] struct {
+	Interval[T]
+	max T // largest Hi in the subtree
+}
+
+// NewIntervalSet creates a new interval set with a custom comparator for
+// the interval bounds
+func NewIntervalSet[T any](compare func(T, T) int) *IntervalSet[T] {
+	requireComparator(compare)
+	tree := NewSet(func(a, b *intervalEntry[T]) int {
+		if c := compare(a.Lo, b.Lo); c != 0 {
+			return c
+		}
+		return compare(a.Hi, b.Hi)
+	})
+	tree.augment = func(n *Node[*intervalEntry[T]]) {
+		e := n.key
+		e.max = e.Hi
+		for _, child := range [...]*Node[*intervalEntry[T]]{n.left, n.right} {
+			if child != nil && compare(child.key.max, e.max) > 0 {
+				e.max = child.key.max
+			}
+		}
+	}
+	return &IntervalSet[T]{tree: tree, compare: compare}
+}
+
+// Size returns the number of intervals in the set
+func (s *IntervalSet[T]) Size() int {
+	return s.tree.Size()
+}
+
+// InsertInterval adds [lo, hi) and returns true if it was not yet present.
+// Empty intervals, with hi not above lo, are not stored.
+func (s *IntervalSet[T]) InsertInterval(lo, hi T) bool {
+	if s.compare(lo, hi) >= 0 {
+		return false
+	}
+	return s.tree.Insert(&intervalEntry[T]{Interval: Interval[T]{Lo: lo, Hi: hi}, max: hi})
+}
+
+// RemoveInterval removes [lo, hi) and returns true if it was present
+func (s *IntervalSet[T]) RemoveInterval(lo, hi T) bool {
+	return s.tree.Remove(&intervalEntry[T]{Interval: Interval[T]{Lo: lo, Hi: hi}})
+}
+
+// ContainsInterval returns true if [lo, hi) is in the set
+func (s *IntervalSet[T]) ContainsInterval(lo, hi T) bool {
+	return s.tree.find(&intervalEntry[T]{Interval: Interval[T]{Lo: lo, Hi: hi}}) != nil
+}
+
+// Stabbing returns the intervals containing point, ordered by their bounds
+func (s *IntervalSet[T]) Stabbing(point T) []Interval[T] {
+	var result []Interval[T]
+	s.overlapping(s.tree.root, point, point, true, func(iv Interval[T]) {
+		result = append(result, iv)
+	})
+	return result
+}
+
+// Overlapping returns the intervals sharing at least one point with
+// [lo, hi), ordered by their bounds
+func (s *IntervalSet[T]) Overlapping(lo, hi T) []Interval[T] {
+	var result []Interval[T]
+	s.overlapping(s.tree.root, lo, hi, false, func(iv Interval[T]) {
+		result = append(result, iv)
+	})
+	return result
+}
+
+// Ascend calls fn for every interval ordered by its bounds until fn
+// returns false
+func (s *IntervalSet[T]) Ascend(fn func(iv Interval[T]) bool) {
+	s.tree.Ascend(func(e *intervalEntry[T]) bool {
+		return fn(e.Interval)
+	})
+}
+
+// overlapping calls fn in order for the intervals below node that overlap
+// [lo, hi), or that contain lo when point is set
+func (s *IntervalSet[T]) overlapping(node *Node[*intervalEntry[T]], lo, hi T, point bool, fn func(Interval[T])) {
+	for node != nil && s.compare(node.key.max, lo) > 0 {
+		s.overlapping(node.left, lo, hi, point, fn)
+		// Intervals further right start at or after this one
+		c := s.compare(node.key.Lo, hi)
+		if c > 0 || c == 0 && !point {
+			return
+		}
+		if s.compare(node.key.Hi, lo) > 0 {
+			fn(node.key.Interval)
+		}
+		node = node.right
+	}
+}
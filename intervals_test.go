@@ -0,0 +1,65 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestIntervalSet(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := NewIntervalSet(cmp.Compare[int])
+	want := map[Interval[int]]bool{}
+	for i := 0; i < 2000; i++ {
+		lo := rng.Intn(1000)
+		iv := Interval[int]{lo, lo + 1 + rng.Intn(50)}
+		if rng.Intn(3) == 0 {
+			if s.RemoveInterval(iv.Lo, iv.Hi) != want[iv] {
+				t.Fatalf("RemoveInterval(%v) disagrees", iv)
+			}
+			delete(want, iv)
+		} else {
+			if s.InsertInterval(iv.Lo, iv.Hi) == want[iv] {
+				t.Fatalf("InsertInterval(%v) disagrees", iv)
+			}
+			want[iv] = true
+		}
+	}
+	if s.Size() != len(want) || s.tree.Validate() != nil {
+		t.Fatalf("Size() = %d, want %d", s.Size(), len(want))
+	}
+	var all []Interval[int]
+	s.Ascend(func(iv Interval[int]) bool {
+		all = append(all, iv)
+		return true
+	})
+	brute := func(keep func(Interval[int]) bool) []Interval[int] {
+		var result []Interval[int]
+		for _, iv := range all {
+			if keep(iv) {
+				result = append(result, iv)
+			}
+		}
+		return result
+	}
+	for i := 0; i < 200; i++ {
+		p := rng.Intn(1100)
+		if got, want := s.Stabbing(p), brute(func(iv Interval[int]) bool { return iv.Lo <= p && p < iv.Hi }); !slices.Equal(got, want) {
+			t.Fatalf("Stabbing(%d) = %v, want %v", p, got, want)
+		}
+		lo := rng.Intn(1100)
+		hi := lo + rng.Intn(30)
+		if got, want := s.Overlapping(lo, hi), brute(func(iv Interval[int]) bool { return iv.Lo < hi && lo < iv.Hi }); !slices.Equal(got, want) {
+			t.Fatalf("Overlapping(%d, %d) = %v, want %v", lo, hi, got, want)
+		}
+	}
+	for iv := range want {
+		if !s.ContainsInterval(iv.Lo, iv.Hi) {
+			t.Fatalf("%v is missing", iv)
+		}
+	}
+	if s.InsertInterval(5, 5) || s.ContainsInterval(5, 5) {
+		t.Fatal("an empty interval was stored")
+	}
+}
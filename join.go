@@ -0,0 +1,262 @@
+package set
+
+import "errors"
+
+// ErrOverlap is returned by Join when the key ranges of two sets overlap
+var ErrOverlap = errors.New("set: key ranges overlap")
+
+// Split moves the elements of s into two new sets, the first holding the
+// elements less than key and the second those greater than or equal to
+// it, and leaves s empty. The tree is cut along the search path for key
+// and the pieces are joined back up, so no element is moved individually
+// and the whole split takes O(log² n) time.
+func (s *Set[T]) Split(key T) (*Set[T], *Set[T]) {
+	s.checkMutable()
+	left, right := s.emptyCopy(), s.emptyCopy()
+	l, eq, r := s.split(s.root, s.canonical(key))
+	if eq != nil {
+		r = s.join(nil, eq, r)
+	}
+	left.root, left.size = s.blacken(l), sizeOf(l)
+	right.root, right.size = s.blacken(r), sizeOf(r)
+	// The pieces hold disjoint parts of the tree of s, so they can own the
+	// nodes that s owned
+	left.gen, left.detached = s.gen, s.detached
+	right.gen, right.detached = s.gen, s.detached
+
+	old := s.size
+	s.root = nil
+	s.size = 0
+	s.mods++
+	s.resized(old)
+	s.cleared(l, r)
+	return left, right
+}
+
+// Merge adds the elements of other to s, keeping the existing element when
+// both sets hold equal ones. other is left unchanged. Instead of inserting
+// the m elements of other one by one, a copy of its tree is split along
+// the tree of s and the pieces are joined back together, which takes
+// O(m log(n/m + 1)) time. Both sets must order their elements the same
+// way.
+func (s *Set[T]) Merge(other *Set[T]) {
+	s.checkMutable()
+	s.absorb(s.copyTree(other.root, nil, s.clone))
+}
+
+// absorb merges the tree rooted at root, whose nodes s takes over, into s
+func (s *Set[T]) absorb(root *Node[T]) {
+	var added []T
+	var collect func(T)
+	if s.observed() {
+		collect = func(key T) {
+			added = append(added, key)
+		}
+	}
+	old := s.size
+	root = s.union(s.root, root, collect)
+	s.root = s.blacken(root)
+	s.size = sizeOf(root)
+	s.mods++
+	s.resized(old)
+	for _, key := range added {
+		s.changed(ChangeInsert, key)
+	}
+}
+
+// Join moves all elements of other into s and leaves other empty. Every
+// element of other must be less than all elements of s or greater than all
+// of them, so the trees can be joined along one spine in O(log n) time;
+// otherwise Join returns ErrOverlap and changes neither set. Both sets must
+// order their elements the same way.
+func (s *Set[T]) Join(other *Set[T]) error {
+	s.checkMutable()
+	other.checkMutable()
+	if other.root == nil {
+		return nil
+	}
+	l, r := s.root, other.root
+	if l != nil && s.compare(s.maximum(l).key, other.minimum(r).key) >= 0 {
+		if s.compare(other.maximum(r).key, s.minimum(l).key) >= 0 {
+			return ErrOverlap
+		}
+		l, r = r, l
+	}
+
+	// The changes are reported while the nodes of other still form a tree
+	// of their own
+	if s.observed() {
+		ascend(other.root, func(key T) bool {
+			s.changed(ChangeInsert, key)
+			return true
+		})
+	}
+	other.cleared(other.root)
+	if other.gen != s.gen {
+		// Nodes other owned may be shared with its snapshots, so s must
+		// not take them over as its own
+		s.gen = generations.Add(1)
+	}
+	s.detached = s.detached || other.detached
+	old, moved := s.size, other.size
+	if l == nil {
+		s.root = r
+	} else {
+		// The smallest element of the right tree becomes the node joining
+		// the two trees
+		scratch := &Set[T]{root: r, gen: s.gen, detached: s.detached, augment: s.augment, nodes: s.nodes}
+		k := scratch.own(nil, r)
+		for k.left != nil {
+			k = scratch.own(k, k.left)
+		}
+		scratch.delete(k)
+		s.root = s.join(l, k, scratch.root)
+	}
+	s.size += moved
+	s.mods++
+	other.root = nil
+	other.size = 0
+	other.mods++
+	other.resized(moved)
+	s.resized(old)
+	return nil
+}
+
+// split cuts the subtree rooted at node into the trees of keys less than
+// key and of keys greater than key, reusing its nodes, and returns the
+// detached node equal to key, if any, in between
+func (s *Set[T]) split(node *Node[T], key T) (*Node[T], *Node[T], *Node[T]) {
+	if node == nil {
+		return nil, nil, nil
+	}
+	node = s.writable(node)
+	left, right := node.left, node.right
+	switch cmp := s.compare(node.key, key); {
+	case cmp < 0:
+		l, eq, r := s.split(right, key)
+		return s.join(left, node, l), eq, r
+	case cmp > 0:
+		l, eq, r := s.split(left, key)
+		return l, eq, s.join(r, node, right)
+	}
+	s.setParent(left, nil)
+	s.setParent(right, nil)
+	node.left, node.right, node.parent = nil, nil, nil
+	return left, node, right
+}
+
+// union returns the root of a tree holding the nodes of a and those of b
+// whose keys are not in a, calling added with each of the latter. Both
+// trees are taken apart; nodes of b equal to a node of a are dropped and
+// handed back to the node allocator.
+func (s *Set[T]) union(a, b *Node[T], added func(T)) *Node[T] {
+	if b == nil {
+		return a
+	}
+	if a == nil {
+		if added != nil {
+			ascend(b, func(key T) bool {
+				added(key)
+				return true
+			})
+		}
+		return b
+	}
+	a = s.writable(a)
+	left, right := a.left, a.right
+	l, eq, r := s.split(b, a.key)
+	if eq != nil && s.nodes != nil {
+		s.nodes.free(eq)
+	}
+	return s.join(s.union(left, l, added), a, s.union(right, r, added))
+}
+
+// join returns the root of a tree holding the nodes of l, the node k and
+// the nodes of r, where all keys in l are less than k's key and all keys
+// in r greater. k, which s must own, is reattached as a new node and
+// s.root is used as scratch space for the rebalancing rotations.
+func (s *Set[T]) join(l, k, r *Node[T]) *Node[T] {
+	// A root can always be blackened, and black roots guarantee that k,
+	// spliced in red below, has no red child
+	l, r = s.blacken(l), s.blacken(r)
+	hl, hr := blackHeight(l), blackHeight(r)
+	if hl == hr {
+		k.left, k.right, k.parent = l, r, nil
+		k.color = Black
+		s.setParent(l, k)
+		s.setParent(r, k)
+		k.size = sizeOf(l) + sizeOf(r) + 1
+		if s.augment != nil {
+			s.augment(k)
+		}
+		s.root = k
+		return k
+	}
+
+	// Walk down the spine of the higher tree facing the other one to the
+	// first black node whose black height matches the lower tree, and
+	// splice k in above it as a red node
+	high, low, h, lh := l, r, hl, hr
+	if hl < hr {
+		high, low, h, lh = r, l, hr, hl
+	}
+	s.root = high
+	var parent *Node[T]
+	c := s.own(nil, high)
+	for h != lh || c != nil && c.color == Red {
+		if c.color == Black {
+			h--
+		}
+		parent = c
+		if hl > hr {
+			c = s.own(parent, c.right)
+		} else {
+			c = s.own(parent, c.left)
+		}
+	}
+	if hl > hr {
+		k.left, k.right = c, low
+		parent.right = k
+	} else {
+		k.left, k.right = low, c
+		parent.left = k
+	}
+	k.parent = parent
+	k.color = Red
+	s.setParent(c, k)
+	s.setParent(low, k)
+	k.size = sizeOf(c) + sizeOf(low) + 1
+	if s.augment != nil {
+		s.augment(k)
+	}
+	for n := parent; n != nil; n = n.parent {
+		n.size += sizeOf(low) + 1
+		if s.augment != nil {
+			s.augment(n)
+		}
+	}
+	s.insertFixup(k)
+	return s.root
+}
+
+// blackHeight returns the number of black nodes on a path from node down
+// to a leaf
+func blackHeight[T any](node *Node[T]) int {
+	h := 0
+	for ; node != nil; node = node.left {
+		if node.color == Black {
+			h++
+		}
+	}
+	return h
+}
+
+// blacken makes node, which may be nil, the black root of its own tree
+func (s *Set[T]) blacken(node *Node[T]) *Node[T] {
+	if node != nil && node.color == Red {
+		node = s.writable(node)
+		node.color = Black
+	}
+	s.setParent(node, nil)
+	return node
+}
@@ -0,0 +1,164 @@
+package set
+
+import (
+	"cmp"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		s := NewSet(cmp.Compare[int])
+		want := map[int]bool{}
+		randomOps(rng, s, want, 300, 500)
+		pivot := rng.Intn(500)
+		left, right := s.Split(pivot)
+		low, high := map[int]bool{}, map[int]bool{}
+		for key := range want {
+			if key < pivot {
+				low[key] = true
+			} else {
+				high[key] = true
+			}
+		}
+		checkSet(t, left, low)
+		checkSet(t, right, high)
+		checkSet(t, s, map[int]bool{})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for round := 0; round < 50; round++ {
+		low, high := NewSet(cmp.Compare[int]), NewSet(cmp.Compare[int])
+		want := map[int]bool{}
+		for i := rng.Intn(200); i > 0; i-- {
+			key := rng.Intn(1000)
+			low.Insert(key)
+			want[key] = true
+		}
+		for i := rng.Intn(200); i > 0; i-- {
+			key := 1000 + rng.Intn(1000)
+			high.Insert(key)
+			want[key] = true
+		}
+		// Either side may be the receiver
+		if round%2 == 0 {
+			low, high = high, low
+		}
+		if err := low.Join(high); err != nil {
+			t.Fatal(err)
+		}
+		checkSet(t, low, want)
+		checkSet(t, high, map[int]bool{})
+	}
+}
+
+func TestJoinOverlap(t *testing.T) {
+	a, b := NewSet(cmp.Compare[int]), NewSet(cmp.Compare[int])
+	a.AddAll(1, 5)
+	b.AddAll(3, 7)
+	if err := a.Join(b); !errors.Is(err, ErrOverlap) {
+		t.Fatalf("Join of overlapping sets: %v", err)
+	}
+	checkSet(t, a, map[int]bool{1: true, 5: true})
+	checkSet(t, b, map[int]bool{3: true, 7: true})
+}
+
+// sumEntry carries the sum of the values in its subtree, kept current by
+// the augment hook
+type sumEntry struct {
+	value, sum int
+}
+
+func newSumSet(values ...int) *Set[*sumEntry] {
+	s := NewSet(func(a, b *sumEntry) int { return cmp.Compare(a.value, b.value) })
+	s.augment = func(n *Node[*sumEntry]) {
+		n.key.sum = n.key.value
+		for _, child := range []*Node[*sumEntry]{n.left, n.right} {
+			if child != nil {
+				n.key.sum += child.key.sum
+			}
+		}
+	}
+	for _, v := range values {
+		s.Insert(&sumEntry{value: v, sum: v})
+	}
+	return s
+}
+
+func checkSums(t *testing.T, n *Node[*sumEntry]) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	sum := n.key.value + checkSums(t, n.left) + checkSums(t, n.right)
+	if n.key.sum != sum {
+		t.Fatalf("subtree sum of %d is %d, want %d", n.key.value, n.key.sum, sum)
+	}
+	return sum
+}
+
+func TestMerge(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for round := 0; round < 50; round++ {
+		a, b := NewSet(cmp.Compare[int]), NewSet(cmp.Compare[int])
+		want, other := map[int]bool{}, map[int]bool{}
+		randomOps(rng, a, want, rng.Intn(300), 400)
+		randomOps(rng, b, other, rng.Intn(300), 400)
+		a.Merge(b)
+		for key := range other {
+			want[key] = true
+		}
+		checkSet(t, a, want)
+		checkSet(t, b, other)
+	}
+}
+
+func TestJoinAugmented(t *testing.T) {
+	a := newSumSet()
+	for i := 0; i < 100; i++ {
+		a.Insert(&sumEntry{value: i, sum: i})
+	}
+	b := newSumSet()
+	for i := 50; i < 300; i++ {
+		b.Insert(&sumEntry{value: i, sum: i})
+	}
+	a.Merge(b)
+	checkSums(t, a.root)
+	c := newSumSet()
+	for i := 1000; i < 1100; i++ {
+		c.Insert(&sumEntry{value: i, sum: i})
+	}
+	if err := a.Join(c); err != nil {
+		t.Fatal(err)
+	}
+	checkSums(t, a.root)
+	left, right := a.Split(&sumEntry{value: 77})
+	checkSums(t, left.root)
+	checkSums(t, right.root)
+	right.InsertBatch([]*sumEntry{{value: 5, sum: 5}, {value: 2000, sum: 2000}, {value: 1050, sum: 1050}})
+	checkSums(t, right.root)
+}
+
+func TestSplitAfterSnapshot(t *testing.T) {
+	s := NewSet(cmp.Compare[int])
+	for i := 0; i < 500; i++ {
+		s.Insert(i)
+	}
+	view := s.Snapshot()
+	left, right := s.Split(250)
+	left.Remove(10)
+	right.Insert(1000)
+	if err := left.Join(right); err != nil {
+		t.Fatal(err)
+	}
+	if err := left.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if view.Size() != 500 || !view.Contains(10) || view.Contains(1000) {
+		t.Fatal("split and join after a snapshot changed the snapshot")
+	}
+}
@@ -0,0 +1,119 @@
+package set
+
+import "container/heap"
+
+// MergedIterator walks any number of sets in a single ascending order,
+// keeping a heap with the next element of every set
+type MergedIterator[T any] struct {
+	heap     cursorHeap[T]
+	distinct bool
+}
+
+type cursor[T any] struct {
+	set   *Set[T]
+	node  *Node[T]
+	index int
+}
+
+// cursorHeap orders cursors by their next element and, among equal
+// elements, by the position of their set in the argument list
+type cursorHeap[T any] struct {
+	cursors []cursor[T]
+	compare func(T, T) int
+}
+
+func (h *cursorHeap[T]) Len() int { return len(h.cursors) }
+
+func (h *cursorHeap[T]) Less(i, j int) bool {
+	a, b := h.cursors[i], h.cursors[j]
+	if cmp := h.compare(a.node.key, b.node.key); cmp != 0 {
+		return cmp < 0
+	}
+	return a.index < b.index
+}
+
+func (h *cursorHeap[T]) Swap(i, j int) { h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i] }
+
+func (h *cursorHeap[T]) Push(x any) { h.cursors = append(h.cursors, x.(cursor[T])) }
+
+func (h *cursorHeap[T]) Pop() any {
+	last := h.cursors[len(h.cursors)-1]
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return last
+}
+
+// MergeIterator returns an iterator over the elements of all sets in
+// ascending order, yielding equal elements once for every set holding
+// them, in the order the sets were given. Each step takes O(log k) time
+// for k sets. The sets are compared with the comparator of the first one
+// and must not be modified while the iterator is in use.
+func MergeIterator[T any](sets ...*Set[T]) *MergedIterator[T] {
+	return merged(false, sets)
+}
+
+// MergeDistinct is like MergeIterator but yields equal elements only once,
+// taking the one from the earliest set
+func MergeDistinct[T any](sets ...*Set[T]) *MergedIterator[T] {
+	return merged(true, sets)
+}
+
+func merged[T any](distinct bool, sets []*Set[T]) *MergedIterator[T] {
+	it := &MergedIterator[T]{distinct: distinct}
+	if len(sets) == 0 {
+		return it
+	}
+	it.heap.compare = sets[0].compare
+	for i, s := range sets {
+		if s.root != nil {
+			it.heap.cursors = append(it.heap.cursors, cursor[T]{set: s, node: s.minimum(s.root), index: i})
+		}
+	}
+	heap.Init(&it.heap)
+	return it
+}
+
+// Valid returns true if the iterator is positioned on an element
+func (it *MergedIterator[T]) Valid() bool {
+	return it.heap.Len() > 0
+}
+
+// Value returns the current element
+func (it *MergedIterator[T]) Value() T {
+	if it.heap.Len() == 0 {
+		var zero T
+		return zero
+	}
+	return it.heap.cursors[0].node.key
+}
+
+// Next moves to the next element
+func (it *MergedIterator[T]) Next() bool {
+	if it.heap.Len() == 0 {
+		return false
+	}
+	key := it.heap.cursors[0].node.key
+	it.step()
+	for it.distinct && it.heap.Len() > 0 && it.heap.compare(it.heap.cursors[0].node.key, key) == 0 {
+		it.step()
+	}
+	return it.heap.Len() > 0
+}
+
+// ToSlice collects the remaining elements
+func (it *MergedIterator[T]) ToSlice() []T {
+	var items []T
+	for ; it.Valid(); it.Next() {
+		items = append(items, it.Value())
+	}
+	return items
+}
+
+// step advances the cursor holding the current element
+func (it *MergedIterator[T]) step() {
+	top := &it.heap.cursors[0]
+	if top.node = top.set.successor(top.node); top.node == nil {
+		heap.Pop(&it.heap)
+	} else {
+		heap.Fix(&it.heap, 0)
+	}
+}
@@ -0,0 +1,51 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestMergeIterator(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var sets []*Set[int]
+	var all []int
+	for i := 0; i < 5; i++ {
+		s := NewSet(cmp.Compare[int])
+		want := map[int]bool{}
+		randomOps(rng, s, want, rng.Intn(50), 100)
+		sets = append(sets, s)
+		all = append(all, s.ToSlice()...)
+	}
+	sets = append(sets, NewSet(cmp.Compare[int]))
+	slices.Sort(all)
+	if got := MergeIterator(sets...).ToSlice(); !slices.Equal(got, all) {
+		t.Fatalf("MergeIterator = %v, want %v", got, all)
+	}
+	distinct := slices.Compact(slices.Clone(all))
+	if got := MergeDistinct(sets...).ToSlice(); !slices.Equal(got, distinct) {
+		t.Fatalf("MergeDistinct = %v, want %v", got, distinct)
+	}
+	if it := MergeIterator[int](); it.Valid() || it.Next() || it.Value() != 0 {
+		t.Fatal("merging no sets is not empty")
+	}
+}
+
+func TestMergeIteratorOrder(t *testing.T) {
+	// Equal elements come in the order of the sets holding them
+	a, b, c := NewSet(compareEntries), NewSet(compareEntries), NewSet(compareEntries)
+	c.Insert(entry{1, "c"})
+	b.Insert(entry{1, "b"})
+	a.Insert(entry{2, "a"})
+	b.Insert(entry{2, "b"})
+	got := MergeIterator(a, b, c).ToSlice()
+	want := []entry{{1, "b"}, {1, "c"}, {2, "a"}, {2, "b"}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("MergeIterator = %v, want %v", got, want)
+	}
+	got = MergeDistinct(c, a, b).ToSlice()
+	if want := []entry{{1, "c"}, {2, "a"}}; !slices.Equal(got, want) {
+		t.Fatalf("MergeDistinct = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package set
+
+// Morton (Z-order) keys interleave the bits of multidimensional coordinates
+// into a single integer, so that points close in space tend to be close in
+// key order. A set of uint64 Morton keys can then answer bounding-box
+// queries by scanning the key ranges returned by MortonRanges2 or
+// MortonRanges3.
+
+// MortonRange is an inclusive range of Morton keys
+type MortonRange struct {
+	Lo, Hi uint64
+}
+
+// Morton2 interleaves x and y into a Morton key, x taking the even bits
+func Morton2(x, y uint32) uint64 {
+	return spread2(x) | spread2(y)<<1
+}
+
+// DecodeMorton2 returns the coordinates encoded in a Morton2 key
+func DecodeMorton2(code uint64) (x, y uint32) {
+	return compact2(code), compact2(code >> 1)
+}
+
+// Morton3 interleaves the low 21 bits of x, y and z into a Morton key
+func Morton3(x, y, z uint32) uint64 {
+	return spread3(x) | spread3(y)<<1 | spread3(z)<<2
+}
+
+// DecodeMorton3 returns the coordinates encoded in a Morton3 key
+func DecodeMorton3(code uint64) (x, y, z uint32) {
+	return compact3(code), compact3(code >> 1), compact3(code >> 2)
+}
+
+// MortonRanges2 decomposes the inclusive box [minX, maxX] × [minY, maxY]
+// into the ascending, non-overlapping Morton key ranges covering exactly
+// the points inside it
+func MortonRanges2(minX, minY, maxX, maxY uint32) []MortonRange {
+	d := mortonDecomposer{
+		dims: 2,
+		min:  []uint64{uint64(minX), uint64(minY)},
+		max:  []uint64{uint64(maxX), uint64(maxY)},
+	}
+	d.split(32, []uint64{0, 0}, 0)
+	return d.ranges
+}
+
+// MortonRanges3 decomposes the inclusive box [minX, maxX] × [minY, maxY] ×
+// [minZ, maxZ] of 21-bit coordinates into ascending Morton key ranges
+func MortonRanges3(minX, minY, minZ, maxX, maxY, maxZ uint32) []MortonRange {
+	const mask = 1<<21 - 1
+	d := mortonDecomposer{
+		dims: 3,
+		min:  []uint64{uint64(minX & mask), uint64(minY & mask), uint64(minZ & mask)},
+		max:  []uint64{uint64(maxX & mask), uint64(maxY & mask), uint64(maxZ & mask)},
+	}
+	d.split(21, []uint64{0, 0, 0}, 0)
+	return d.ranges
+}
+
+// QueryMortonRanges calls fn, in ascending order, for every Morton key in s
+// that falls in one of ranges, until fn returns false
+func QueryMortonRanges(s *Set[uint64], ranges []MortonRange, fn func(code uint64) bool) {
+	for _, r := range ranges {
+		for node := s.lowerBound(r.Lo); node != nil; node = s.successor(node) {
+			code := node.key
+			if code > r.Hi {
+				break
+			}
+			if !fn(code) {
+				return
+			}
+		}
+	}
+}
+
+// mortonDecomposer recursively splits the key space into cells, emitting
+// the key range of every cell that lies fully inside the box
+type mortonDecomposer struct {
+	dims     int
+	min, max []uint64
+	ranges   []MortonRange
+}
+
+func (d *mortonDecomposer) split(level uint, origin []uint64, prefix uint64) {
+	side := uint64(1) << level
+	inside := true
+	for i := 0; i < d.dims; i++ {
+		if origin[i] > d.max[i] || origin[i]+side-1 < d.min[i] {
+			return
+		}
+		if origin[i] < d.min[i] || origin[i]+side-1 > d.max[i] {
+			inside = false
+		}
+	}
+	if inside {
+		d.emit(prefix, prefix+(uint64(1)<<(uint(d.dims)*level)-1))
+		return
+	}
+	half := side >> 1
+	for child := uint64(0); child < 1<<uint(d.dims); child++ {
+		childOrigin := make([]uint64, d.dims)
+		for i := range childOrigin {
+			childOrigin[i] = origin[i] + (child>>uint(i)&1)*half
+		}
+		d.split(level-1, childOrigin, prefix|child<<(uint(d.dims)*(level-1)))
+	}
+}
+
+func (d *mortonDecomposer) emit(lo, hi uint64) {
+	if n := len(d.ranges); n > 0 && d.ranges[n-1].Hi+1 == lo {
+		d.ranges[n-1].Hi = hi
+		return
+	}
+	d.ranges = append(d.ranges, MortonRange{Lo: lo, Hi: hi})
+}
+
+func spread2(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<16) & 0x0000ffff0000ffff
+	x = (x | x<<8) & 0x00ff00ff00ff00ff
+	x = (x | x<<4) & 0x0f0f0f0f0f0f0f0f
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
+
+func compact2(x uint64) uint32 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0f0f0f0f0f0f0f0f
+	x = (x | x>>4) & 0x00ff00ff00ff00ff
+	x = (x | x>>8) & 0x0000ffff0000ffff
+	x = (x | x>>16) & 0x00000000ffffffff
+	return uint32(x)
+}
+
+func spread3(v uint32) uint64 {
+	x := uint64(v) & 0x1fffff
+	x = (x | x<<32) & 0x1f00000000ffff
+	x = (x | x<<16) & 0x1f0000ff0000ff
+	x = (x | x<<8) & 0x100f00f00f00f00f
+	x = (x | x<<4) & 0x10c30c30c30c30c3
+	x = (x | x<<2) & 0x1249249249249249
+	return x
+}
+
+func compact3(x uint64) uint32 {
+	x &= 0x1249249249249249
+	x = (x | x>>2) & 0x10c30c30c30c30c3
+	x = (x | x>>4) & 0x100f00f00f00f00f
+	x = (x | x>>8) & 0x1f0000ff0000ff
+	x = (x | x>>16) & 0x1f00000000ffff
+	x = (x | x>>32) & 0x1fffff
+	return uint32(x)
+}
@@ -0,0 +1,69 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestMortonCodes(t *testing.T) {
+	if Morton2(1, 0) != 1 || Morton2(0, 1) != 2 || Morton2(3, 3) != 15 || Morton3(1, 1, 1) != 7 {
+		t.Fatal("wrong bit interleaving")
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y, z := rng.Uint32(), rng.Uint32(), rng.Uint32()&(1<<21-1)
+		if gx, gy := DecodeMorton2(Morton2(x, y)); gx != x || gy != y {
+			t.Fatalf("Morton2(%d, %d) decoded to %d, %d", x, y, gx, gy)
+		}
+		if gx, gy, gz := DecodeMorton3(Morton3(x&(1<<21-1), y&(1<<21-1), z)); gx != x&(1<<21-1) || gy != y&(1<<21-1) || gz != z {
+			t.Fatalf("Morton3 round trip of %d, %d, %d gave %d, %d, %d", x, y, z, gx, gy, gz)
+		}
+	}
+}
+
+func TestMortonRanges(t *testing.T) {
+	s := NewSet(cmp.Compare[uint64])
+	for x := uint32(0); x < 32; x++ {
+		for y := uint32(0); y < 32; y++ {
+			s.Insert(Morton2(x, y))
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		minX, minY := uint32(rng.Intn(32)), uint32(rng.Intn(32))
+		maxX, maxY := minX+uint32(rng.Intn(int(32-minX))), minY+uint32(rng.Intn(int(32-minY)))
+		ranges := MortonRanges2(minX, minY, maxX, maxY)
+		for j := 1; j < len(ranges); j++ {
+			if ranges[j].Lo <= ranges[j-1].Hi+1 {
+				t.Fatalf("ranges %v are not ascending and disjoint", ranges)
+			}
+		}
+		var got []uint64
+		QueryMortonRanges(s, ranges, func(code uint64) bool {
+			got = append(got, code)
+			return true
+		})
+		var want []uint64
+		for x := minX; x <= maxX; x++ {
+			for y := minY; y <= maxY; y++ {
+				want = append(want, Morton2(x, y))
+			}
+		}
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Fatalf("box %d,%d..%d,%d found %d points, want %d", minX, minY, maxX, maxY, len(got), len(want))
+		}
+		var size uint64
+		for _, r := range ranges {
+			size += r.Hi - r.Lo + 1
+		}
+		if size != uint64(len(want)) {
+			t.Fatalf("box %d,%d..%d,%d covers %d keys, want %d", minX, minY, maxX, maxY, size, len(want))
+		}
+	}
+	if ranges := MortonRanges3(0, 0, 0, 1, 1, 1); len(ranges) != 1 || ranges[0] != (MortonRange{0, 7}) {
+		t.Fatalf("MortonRanges3 of the unit cube = %v", ranges)
+	}
+}
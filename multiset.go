@@ -0,0 +1,100 @@
+package set
+
+// MultiSet is an ordered collection that allows duplicate keys. Each
+// distinct key is stored once in a red-black tree together with the number
+// of times it occurs, so repeated keys cost no extra nodes.
+type MultiSet[T any] struct {
+	entries *Set[*multiEntry[T]]
+	size    int
+}
+
+type multiEntry[T any] struct {
+	key   T
+	count int
+}
+
+// NewMultiSet creates a new multiset with a custom comparator
+func NewMultiSet[T any](compare func(T, T) int) *MultiSet[T] {
+	requireComparator(compare)
+	return &MultiSet[T]{
+		entries: NewSet(func(a, b *multiEntry[T]) int {
+			return compare(a.key, b.key)
+		}),
+	}
+}
+
+// Size returns the number of elements, counting duplicates
+func (m *MultiSet[T]) Size() int {
+	return m.size
+}
+
+// Distinct returns the number of distinct keys
+func (m *MultiSet[T]) Distinct() int {
+	return m.entries.Size()
+}
+
+// IsEmpty returns true if the multiset has no elements
+func (m *MultiSet[T]) IsEmpty() bool {
+	return m.size == 0
+}
+
+// Clear removes all elements from the multiset
+func (m *MultiSet[T]) Clear() {
+	m.entries.Clear()
+	m.size = 0
+}
+
+// Insert adds one occurrence of key and returns its new count
+func (m *MultiSet[T]) Insert(key T) int {
+	node, _ := m.entries.insert(&multiEntry[T]{key: key})
+	node.key.count++
+	m.size++
+	return node.key.count
+}
+
+// Count returns the number of occurrences of key
+func (m *MultiSet[T]) Count(key T) int {
+	if node := m.entries.find(&multiEntry[T]{key: key}); node != nil {
+		return node.key.count
+	}
+	return 0
+}
+
+// Contains returns true if key occurs at least once
+func (m *MultiSet[T]) Contains(key T) bool {
+	return m.Count(key) > 0
+}
+
+// RemoveOne removes one occurrence of key and returns true if there was one
+func (m *MultiSet[T]) RemoveOne(key T) bool {
+	node := m.entries.find(&multiEntry[T]{key: key})
+	if node == nil {
+		return false
+	}
+	node.key.count--
+	m.size--
+	if node.key.count == 0 {
+		m.entries.removeNode(node)
+	}
+	return true
+}
+
+// RemoveAll removes every occurrence of key and returns how many there were
+func (m *MultiSet[T]) RemoveAll(key T) int {
+	node := m.entries.find(&multiEntry[T]{key: key})
+	if node == nil {
+		return 0
+	}
+	count := node.key.count
+	m.size -= count
+	m.entries.removeNode(node)
+	return count
+}
+
+// Ascend calls fn for every distinct key and its count in ascending order
+// until fn returns false
+func (m *MultiSet[T]) Ascend(fn func(key T, count int) bool) {
+	m.entries.Ascend(func(e *multiEntry[T]) bool {
+		return fn(e.key, e.count)
+	})
+}
@@ -0,0 +1,40 @@
+package set
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMultiSet(t *testing.T) {
+	m := NewMultiSet(strings.Compare)
+	for _, w := range []string{"b", "a", "b", "c", "b"} {
+		m.Insert(w)
+	}
+	if m.Size() != 5 || m.Distinct() != 3 || m.Count("b") != 3 || m.Count("x") != 0 {
+		t.Fatalf("Size() = %d, Distinct() = %d, Count(b) = %d", m.Size(), m.Distinct(), m.Count("b"))
+	}
+	if n := m.Insert("a"); n != 2 {
+		t.Fatalf("Insert returned count %d", n)
+	}
+	if !m.RemoveOne("c") || m.Contains("c") || m.RemoveOne("c") {
+		t.Fatal("RemoveOne of the last occurrence went wrong")
+	}
+	var got []string
+	m.Ascend(func(key string, count int) bool {
+		got = append(got, strings.Repeat(key, count))
+		return true
+	})
+	if strings.Join(got, ",") != "aa,bbb" {
+		t.Fatalf("Ascend saw %v", got)
+	}
+	if n := m.RemoveAll("b"); n != 3 || m.Size() != 2 || m.Distinct() != 1 {
+		t.Fatalf("RemoveAll removed %d, Size() = %d", n, m.Size())
+	}
+	if m.RemoveAll("b") != 0 {
+		t.Fatal("RemoveAll of a missing key removed something")
+	}
+	m.Clear()
+	if !m.IsEmpty() || m.Count("a") != 0 {
+		t.Fatal("Clear left occurrences")
+	}
+}
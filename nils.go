@@ -0,0 +1,72 @@
+package set
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrNilComparator is the panic value of constructors given a nil
+// comparator, raised up front rather than on the first insert
+var ErrNilComparator = errors.New("set: nil comparator")
+
+// ErrNilKey is the panic value of operations given a nil key by a set
+// created with WithNilKeys(NilKeysRejected)
+var ErrNilKey = errors.New("set: nil key")
+
+// NilKeyPolicy decides how a set treats nil keys of pointer, interface,
+// map, slice, channel and function element types
+type NilKeyPolicy int
+
+const (
+	// NilKeysByComparator passes nil keys to the comparator like any other
+	// key. It is the default.
+	NilKeysByComparator NilKeyPolicy = iota
+	// NilKeysRejected panics with ErrNilKey when a nil key is inserted or
+	// looked up
+	NilKeysRejected
+	// NilKeysFirst orders nil before every other key without calling the
+	// comparator on it, so the comparator may dereference its arguments
+	NilKeysFirst
+)
+
+// WithNilKeys sets the policy for nil keys. It must be given after any
+// option replacing the comparator.
+func WithNilKeys[T any](policy NilKeyPolicy) Option[T] {
+	return func(s *Set[T]) {
+		s.nilKeys = policy
+		if policy != NilKeysFirst {
+			return
+		}
+		compare := s.compare
+		s.compare = func(a, b T) int {
+			switch aNil, bNil := isNil(a), isNil(b); {
+			case aNil && bNil:
+				return 0
+			case aNil:
+				return -1
+			case bNil:
+				return 1
+			}
+			return compare(a, b)
+		}
+	}
+}
+
+// requireComparator panics with ErrNilComparator if compare is nil
+func requireComparator[T any](compare func(T, T) int) {
+	if compare == nil {
+		panic(ErrNilComparator)
+	}
+}
+
+// isNil reports whether key is nil, for element types that can be
+func isNil[T any](key T) bool {
+	v := reflect.ValueOf(any(key))
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package set
+
+import (
+	"cmp"
+	"testing"
+)
+
+func comparePointers(a, b *int) int {
+	return cmp.Compare(*a, *b)
+}
+
+func TestNilKeysFirst(t *testing.T) {
+	one, two := 1, 2
+	// comparePointers dereferences its arguments, so it must never see nil
+	s := NewSetWithOptions(comparePointers, WithNilKeys[*int](NilKeysFirst))
+	s.Insert(&two)
+	s.Insert(nil)
+	s.Insert(&one)
+	if s.Insert(nil) || !s.Contains(nil) || s.Size() != 3 {
+		t.Fatal("nil is not a single element of the set")
+	}
+	if lo, _ := s.Min(); lo != nil {
+		t.Fatalf("Min = %v, want nil", lo)
+	}
+	if got := s.ToSlice(); got[1] != &one || got[2] != &two {
+		t.Fatalf("elements after nil are %v", got[1:])
+	}
+	s.Remove(nil)
+	if s.Contains(nil) || s.Validate() != nil {
+		t.Fatal("removing nil failed")
+	}
+}
+
+func TestNilKeysRejected(t *testing.T) {
+	one := 1
+	s := NewSetWithOptions(comparePointers, WithNilKeys[*int](NilKeysRejected))
+	s.Insert(&one)
+	mustPanicWith(t, ErrNilKey, func() { s.Insert(nil) })
+	mustPanicWith(t, ErrNilKey, func() { s.Contains(nil) })
+	if err := s.InsertE(nil); err != ErrNilKey {
+		t.Fatalf("InsertE(nil) = %v", err)
+	}
+
+	// Interfaces holding nil and empty ones are both nil keys
+	var e error
+	errs := NewSetWithOptions(func(a, b error) int { return cmp.Compare(a.Error(), b.Error()) }, WithNilKeys[error](NilKeysRejected))
+	mustPanicWith(t, ErrNilKey, func() { errs.Insert(e) })
+	if !isNil((*int)(nil)) || isNil(0) || !isNil([]int(nil)) || isNil([]int{}) {
+		t.Fatal("isNil misclassifies keys")
+	}
+}
+
+func TestNilComparator(t *testing.T) {
+	mustPanicWith(t, ErrNilComparator, func() { NewSet[int](nil) })
+	mustPanicWith(t, ErrNilComparator, func() { NewOrderedMap[int, int](nil) })
+}
@@ -0,0 +1,58 @@
+package set
+
+import "errors"
+
+// Option configures a Set created with NewSetWithOptions
+type Option[T any] func(*Set[T])
+
+// NewSetWithOptions creates a new set with a custom comparator and options
+func NewSetWithOptions[T any](compare func(T, T) int, opts ...Option[T]) *Set[T] {
+	s := NewSet(compare)
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithKeyClone makes the set store a copy of every inserted key, so callers
+// mutating a key after insertion can't corrupt the tree's ordering
+func WithKeyClone[T any](clone func(T) T) Option[T] {
+	return func(s *Set[T]) {
+		s.clone = clone
+	}
+}
+
+// WithNormalize makes the set apply normalize to every key before comparing
+// or storing it, so keys are kept in a single canonical form
+func WithNormalize[T any](normalize func(T) T) Option[T] {
+	return func(s *Set[T]) {
+		s.normalize = normalize
+	}
+}
+
+// WithLoader makes lookups fall through to load on a miss. When load
+// reports success the returned element is inserted into the set, turning
+// the set into a read-through cache over a backing store. An element that
+// is not equal to the key looked up is ignored, as if load had failed.
+func WithLoader[T any](load func(key T) (T, bool)) Option[T] {
+	return func(s *Set[T]) {
+		s.loader = load
+	}
+}
+
+// ErrFull is returned when adding elements to a set created WithMaxSize
+// would take it past its limit
+var ErrFull = errors.New("set: set is full")
+
+// WithMaxSize limits the set to n elements, with n below 1 meaning no
+// limit. Inserting a new element into a full set fails: Insert returns
+// false, InsertE returns ErrFull, and BuildFromSorted, ImportFrom and the
+// decoders fail with ErrFull for input that is too large. Merge, Join and
+// InsertBatch move whole trees and are not limited. The limit is not
+// carried over to the sets built by Reorder or Fork. For a set that makes
+// room instead, see NewBoundedSet.
+func WithMaxSize[T any](n int) Option[T] {
+	return func(s *Set[T]) {
+		s.maxSize = n
+	}
+}
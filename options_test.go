@@ -0,0 +1,136 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWithKeyClone(t *testing.T) {
+	s := NewSetWithOptions(slices.Compare[[]int], WithKeyClone(slices.Clone[[]int]))
+	key := []int{1, 2}
+	s.Insert(key)
+	key[0] = 9
+	if !s.Contains([]int{1, 2}) || s.Contains([]int{9, 2}) {
+		t.Fatal("mutating an inserted key changed the set")
+	}
+	stored, _ := s.Find([]int{1, 2})
+	if &stored[0] == &key[0] {
+		t.Fatal("the set stored the caller's key")
+	}
+}
+
+func TestWithNormalize(t *testing.T) {
+	s := NewSetWithOptions(strings.Compare, WithNormalize(strings.ToLower))
+	s.Insert("Hello")
+	if s.Insert("HELLO") {
+		t.Fatal("an equal key in another case was inserted")
+	}
+	if !s.Contains("hElLo") || s.Size() != 1 {
+		t.Fatalf("set holds %v", s.ToSlice())
+	}
+	if got, _ := s.Find("HELLO"); got != "hello" {
+		t.Fatalf("stored key %q, want the normalized form", got)
+	}
+	if !s.Remove("HeLLo") || !s.IsEmpty() {
+		t.Fatal("Remove ignores normalization")
+	}
+}
+
+func TestLoader(t *testing.T) {
+	even := NewSetWithOptions(cmp.Compare[int], WithLoader(func(key int) (int, bool) {
+		return key, key%2 == 0
+	}))
+	if !even.Contains(4) || even.Contains(3) || even.Size() != 1 {
+		t.Fatalf("loader lookups left %v", even)
+	}
+	// A loader returning another key is ignored
+	wrong := NewSetWithOptions(cmp.Compare[int], WithLoader(func(key int) (int, bool) {
+		return key + 1, true
+	}))
+	if wrong.Contains(5) || wrong.Size() != 0 {
+		t.Fatalf("mismatched load inserted into %v", wrong)
+	}
+}
+
+func TestLoaderInsideScope(t *testing.T) {
+	loads := 0
+	s := NewSetWithOptions(cmp.Compare[int], WithLoader(func(key int) (int, bool) {
+		loads++
+		return key, true
+	}))
+	s.Locked(func(ro ReadOnlySet[int]) {
+		if ro.Contains(1) {
+			t.Fatal("a lookup inside a scope loaded an element")
+		}
+	})
+	if loads != 0 || !s.Contains(1) || loads != 1 || !s.Contains(1) || loads != 1 {
+		t.Fatalf("loader ran %d times", loads)
+	}
+}
+
+func TestInitialCapacity(t *testing.T) {
+	// AllocsPerRun makes one extra call to warm up
+	s := NewSetWithOptions(cmp.Compare[int], WithInitialCapacity[int](101))
+	next := 0
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Insert(next)
+		next++
+	})
+	if allocs != 0 {
+		t.Fatalf("filling a preallocated set took %v allocations per insert", allocs)
+	}
+	if s.Size() != 101 {
+		t.Fatalf("size %d, want 101", s.Size())
+	}
+}
+
+func TestMaxSize(t *testing.T) {
+	s := NewSetWithOptions(cmp.Compare[int], WithMaxSize[int](2))
+	if !s.Insert(1) || !s.Insert(2) || s.Insert(3) || s.Contains(3) {
+		t.Fatalf("a set limited to 2 holds %v", s.ToSlice())
+	}
+	s.Remove(1)
+	if !s.Insert(3) {
+		t.Fatal("a removal did not make room")
+	}
+}
+
+func TestStatsCollection(t *testing.T) {
+	s := NewSetWithOptions(cmp.Compare[int], WithStatsCollection[int]())
+	s.Insert(1)
+	s.Insert(1)
+	s.Insert(2)
+	s.Remove(2)
+	s.Remove(5)
+	s.Contains(1)
+	s.Contains(7)
+	s.Find(1)
+	st := s.Stats()
+	if st.Inserts != 3 || st.Inserted != 2 || st.Removes != 2 || st.Removed != 1 || st.Lookups != 3 || st.Hits != 2 {
+		t.Fatalf("counters %+v", st)
+	}
+	if st := intSet(1).Stats(); st.Inserts != 0 {
+		t.Fatalf("a set without the option counted %d inserts", st.Inserts)
+	}
+}
+
+func TestSafeIterators(t *testing.T) {
+	s := NewSetWithOptions(cmp.Compare[int], WithSafeIterators[int]())
+	for i := 0; i < 10; i++ {
+		s.Insert(i)
+	}
+	// An iterator whose element stays follows inserts and removals
+	// elsewhere; one whose element goes moves to the following one
+	stays, goes := s.LowerBound(3), s.LowerBound(5)
+	s.Remove(4)
+	s.Insert(100)
+	s.Remove(5)
+	if stays.Value() != 3 || !stays.Next() || stays.Value() != 6 {
+		t.Fatal("the iterator on 3 did not follow the changes")
+	}
+	if goes.Value() != 6 || !goes.Prev() || goes.Value() != 3 {
+		t.Fatal("the iterator on the removed 5 did not move on")
+	}
+}
@@ -0,0 +1,272 @@
+package set
+
+// OrderedMap is a map whose keys are kept in order by a red-black tree. It
+// supports the same ordered queries as Set, returning the value stored with
+// each key.
+type OrderedMap[K, V any] struct {
+	entries *Set[*mapEntry[K, V]]
+}
+
+type mapEntry[K, V any] struct {
+	key   K
+	value V
+}
+
+// MapIterator is a bidirectional iterator over the entries of an OrderedMap
+type MapIterator[K, V any] struct {
+	it *Iterator[*mapEntry[K, V]]
+}
+
+// NewOrderedMap creates a new ordered map with a custom key comparator
+func NewOrderedMap[K, V any](compare func(K, K) int) *OrderedMap[K, V] {
+	requireComparator(compare)
+	return &OrderedMap[K, V]{
+		entries: NewSet(func(a, b *mapEntry[K, V]) int {
+			return compare(a.key, b.key)
+		}),
+	}
+}
+
+// Len returns the number of entries in the map
+func (m *OrderedMap[K, V]) Len() int {
+	return m.entries.Size()
+}
+
+// IsEmpty returns true if the map has no entries
+func (m *OrderedMap[K, V]) IsEmpty() bool {
+	return m.entries.IsEmpty()
+}
+
+// Clear removes all entries from the map
+func (m *OrderedMap[K, V]) Clear() {
+	m.entries.Clear()
+}
+
+// Get returns the value stored with key
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	return valueOf(m.entries.find(&mapEntry[K, V]{key: key}))
+}
+
+// Contains returns true if key is in the map
+func (m *OrderedMap[K, V]) Contains(key K) bool {
+	return m.entries.find(&mapEntry[K, V]{key: key}) != nil
+}
+
+// Put stores value with key and returns true if the key was not yet present
+func (m *OrderedMap[K, V]) Put(key K, value V) bool {
+	node, inserted := m.entries.insert(&mapEntry[K, V]{key: key})
+	node.key.value = value
+	return inserted
+}
+
+// Delete removes key and returns the value that was stored with it
+func (m *OrderedMap[K, V]) Delete(key K) (V, bool) {
+	node := m.entries.find(&mapEntry[K, V]{key: key})
+	if node == nil {
+		var zero V
+		return zero, false
+	}
+	value := node.key.value
+	m.entries.removeNode(node)
+	return value, true
+}
+
+// Min returns the entry with the smallest key
+func (m *OrderedMap[K, V]) Min() (K, V, bool) {
+	if m.entries.root == nil {
+		return entryOf[K, V](nil)
+	}
+	return entryOf(m.entries.minimum(m.entries.root))
+}
+
+// Max returns the entry with the largest key
+func (m *OrderedMap[K, V]) Max() (K, V, bool) {
+	if m.entries.root == nil {
+		return entryOf[K, V](nil)
+	}
+	return entryOf(m.entries.maximum(m.entries.root))
+}
+
+// Floor returns the entry with the greatest key less than or equal to key
+func (m *OrderedMap[K, V]) Floor(key K) (K, V, bool) {
+	return entryOf(m.entries.floor(&mapEntry[K, V]{key: key}, true))
+}
+
+// Ceiling returns the entry with the smallest key greater than or equal to
+// key
+func (m *OrderedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	return entryOf(m.entries.lowerBound(&mapEntry[K, V]{key: key}))
+}
+
+// Higher returns the entry with the smallest key strictly greater than key
+func (m *OrderedMap[K, V]) Higher(key K) (K, V, bool) {
+	return entryOf(m.entries.upperBound(&mapEntry[K, V]{key: key}))
+}
+
+// Lower returns the entry with the greatest key strictly less than key
+func (m *OrderedMap[K, V]) Lower(key K) (K, V, bool) {
+	return entryOf(m.entries.floor(&mapEntry[K, V]{key: key}, false))
+}
+
+// At returns the entry at index i in key order
+func (m *OrderedMap[K, V]) At(i int) (K, V, bool) {
+	return entryOf(m.entries.at(i))
+}
+
+// Rank returns the number of keys less than key
+func (m *OrderedMap[K, V]) Rank(key K) int {
+	return m.entries.rank(&mapEntry[K, V]{key: key})
+}
+
+// Begin returns an iterator to the entry with the smallest key
+func (m *OrderedMap[K, V]) Begin() *MapIterator[K, V] {
+	return &MapIterator[K, V]{it: m.entries.Begin()}
+}
+
+// End returns an iterator past the entry with the largest key
+func (m *OrderedMap[K, V]) End() *MapIterator[K, V] {
+	return &MapIterator[K, V]{it: m.entries.End()}
+}
+
+// RBegin returns a reverse iterator to the entry with the largest key
+func (m *OrderedMap[K, V]) RBegin() *MapIterator[K, V] {
+	return &MapIterator[K, V]{it: m.entries.RBegin()}
+}
+
+// REnd returns a reverse iterator before the entry with the smallest key
+func (m *OrderedMap[K, V]) REnd() *MapIterator[K, V] {
+	return &MapIterator[K, V]{it: m.entries.REnd()}
+}
+
+// LowerBound returns an iterator to the first entry whose key is not less
+// than key
+func (m *OrderedMap[K, V]) LowerBound(key K) *MapIterator[K, V] {
+	return &MapIterator[K, V]{it: m.entries.LowerBound(&mapEntry[K, V]{key: key})}
+}
+
+// UpperBound returns an iterator to the first entry whose key is greater
+// than key
+func (m *OrderedMap[K, V]) UpperBound(key K) *MapIterator[K, V] {
+	return &MapIterator[K, V]{it: m.entries.UpperBound(&mapEntry[K, V]{key: key})}
+}
+
+// Ascend calls fn for every entry in ascending key order until fn returns
+// false
+func (m *OrderedMap[K, V]) Ascend(fn func(key K, value V) bool) {
+	m.entries.Ascend(func(e *mapEntry[K, V]) bool {
+		return fn(e.key, e.value)
+	})
+}
+
+// Descend calls fn for every entry in descending key order until fn
+// returns false
+func (m *OrderedMap[K, V]) Descend(fn func(key K, value V) bool) {
+	m.entries.Descend(func(e *mapEntry[K, V]) bool {
+		return fn(e.key, e.value)
+	})
+}
+
+// AscendRange calls fn for every entry with a key in [from, to) in
+// ascending order until fn returns false
+func (m *OrderedMap[K, V]) AscendRange(from, to K, fn func(key K, value V) bool) {
+	m.entries.AscendRange(&mapEntry[K, V]{key: from}, &mapEntry[K, V]{key: to}, func(e *mapEntry[K, V]) bool {
+		return fn(e.key, e.value)
+	})
+}
+
+// UpdateRange replaces the value of every entry with a key in [from, to)
+// by fn applied to its key and value, walking the range once in ascending
+// order
+func (m *OrderedMap[K, V]) UpdateRange(from, to K, fn func(key K, value V) V) {
+	m.entries.AscendRange(&mapEntry[K, V]{key: from}, &mapEntry[K, V]{key: to}, func(e *mapEntry[K, V]) bool {
+		e.value = fn(e.key, e.value)
+		return true
+	})
+}
+
+// Keys returns the keys in ascending order
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.Ascend(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns the values in ascending key order
+func (m *OrderedMap[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	m.Ascend(func(_ K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Valid returns true if the iterator is positioned on an entry
+func (it *MapIterator[K, V]) Valid() bool {
+	return it.it.Valid()
+}
+
+// Key returns the key of the current entry. Like Iterator.Value, it panics
+// with ErrConcurrentModification if the map was modified other than
+// through the iterator.
+func (it *MapIterator[K, V]) Key() K {
+	key, _, _ := entryOf(it.current())
+	return key
+}
+
+// Value returns the value of the current entry, checked like Key
+func (it *MapIterator[K, V]) Value() V {
+	value, _ := valueOf(it.current())
+	return value
+}
+
+// SetValue replaces the value of the current entry in place, without
+// looking its key up again, and returns false if the iterator is not
+// positioned on an entry. It is checked like Key. Values play no part in
+// the order, so this does not invalidate other iterators.
+func (it *MapIterator[K, V]) SetValue(value V) bool {
+	node := it.current()
+	if node == nil {
+		return false
+	}
+	node.key.value = value
+	return true
+}
+
+// current returns the node of the current entry, re-seeking a safe
+// iterator whose entry was removed the way Iterator.Value does
+func (it *MapIterator[K, V]) current() *Node[*mapEntry[K, V]] {
+	if it.it.node != nil && it.it.checkModified() {
+		it.it.relocate(true)
+	}
+	return it.it.node
+}
+
+// Next moves to the next entry
+func (it *MapIterator[K, V]) Next() bool {
+	return it.it.Next()
+}
+
+// Prev moves to the previous entry
+func (it *MapIterator[K, V]) Prev() bool {
+	return it.it.Prev()
+}
+
+// entryOf returns the key and value of node, reporting false for a nil node
+func entryOf[K, V any](node *Node[*mapEntry[K, V]]) (K, V, bool) {
+	if node == nil {
+		var key K
+		var value V
+		return key, value, false
+	}
+	return node.key.key, node.key.value, true
+}
+
+// valueOf returns the value of node, reporting false for a nil node
+func valueOf[K, V any](node *Node[*mapEntry[K, V]]) (V, bool) {
+	_, value, ok := entryOf(node)
+	return value, ok
+}
@@ -0,0 +1,131 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[int, string](cmp.Compare[int])
+	for i, v := range []string{"zero", "one", "two", "three", "four"} {
+		if !m.Put(10*i, v) {
+			t.Fatalf("Put(%d) found an existing key", 10*i)
+		}
+	}
+	if m.Put(20, "TWO") {
+		t.Fatal("Put of an existing key reported an insert")
+	}
+	if v, ok := m.Get(20); !ok || v != "TWO" || m.Len() != 5 {
+		t.Fatalf("Get(20) = %q, %v", v, ok)
+	}
+	if _, ok := m.Get(25); ok || m.Contains(25) {
+		t.Fatal("a missing key was found")
+	}
+	for _, c := range []struct {
+		name string
+		find func(int) (int, string, bool)
+		key  int
+		ok   bool
+		want int
+	}{
+		{"Floor", m.Floor, 25, true, 20},
+		{"Ceiling", m.Ceiling, 25, true, 30},
+		{"Higher", m.Higher, 40, false, 0},
+		{"Lower", m.Lower, 0, false, 0},
+	} {
+		if key, _, ok := c.find(c.key); ok != c.ok || key != c.want {
+			t.Errorf("%s(%d) = %d, %v", c.name, c.key, key, ok)
+		}
+	}
+	if k, v, _ := m.At(3); k != 30 || v != "three" || m.Rank(30) != 3 {
+		t.Fatalf("At(3) = %d, %q", k, v)
+	}
+	if k, _, _ := m.Min(); k != 0 {
+		t.Fatalf("Min() = %d", k)
+	}
+	if k, v, _ := m.Max(); k != 40 || v != "four" {
+		t.Fatalf("Max() = %d, %q", k, v)
+	}
+
+	m.UpdateRange(10, 30, func(k int, v string) string { return v + "!" })
+	if got := m.Values(); !slices.Equal(got, []string{"zero", "one!", "TWO!", "three", "four"}) {
+		t.Fatalf("Values() after UpdateRange = %v", got)
+	}
+	var desc []int
+	m.Descend(func(k int, _ string) bool {
+		desc = append(desc, k)
+		return k > 20
+	})
+	if !slices.Equal(desc, []int{40, 30, 20}) {
+		t.Fatalf("Descend saw %v", desc)
+	}
+	if v, ok := m.Delete(0); !ok || v != "zero" {
+		t.Fatalf("Delete(0) = %q, %v", v, ok)
+	}
+	if _, ok := m.Delete(0); ok {
+		t.Fatal("Delete of a missing key succeeded")
+	}
+	if got := m.Keys(); !slices.Equal(got, []int{10, 20, 30, 40}) {
+		t.Fatalf("Keys() = %v", got)
+	}
+}
+
+func TestMapIterator(t *testing.T) {
+	m := NewOrderedMap[string, int](cmp.Compare[string])
+	m.Put("a", 1)
+	m.Put("b", 2)
+	m.Put("c", 3)
+	for it := m.LowerBound("b"); it.Valid(); it.Next() {
+		it.SetValue(it.Value() * 10)
+	}
+	var got []int
+	for it := m.RBegin(); it.Valid(); it.Next() {
+		got = append(got, it.Value())
+	}
+	if !slices.Equal(got, []int{30, 20, 1}) {
+		t.Fatalf("reverse iteration saw %v", got)
+	}
+	it := m.UpperBound("c")
+	if it.Valid() || it.SetValue(0) {
+		t.Fatal("an iterator past the end took a value")
+	}
+	it = m.Begin()
+	if it.Key() != "a" || !it.Next() || it.Key() != "b" || !it.Prev() || it.Key() != "a" {
+		t.Fatal("stepping a map iterator went wrong")
+	}
+}
+
+func TestMapIteratorModified(t *testing.T) {
+	m := NewOrderedMap[string, int](cmp.Compare[string])
+	m.Put("a", 1)
+	m.Put("c", 3)
+	it := m.Begin()
+	m.Put("b", 2)
+	mustPanicWith(t, ErrConcurrentModification, func() { it.Key() })
+	mustPanicWith(t, ErrConcurrentModification, func() { it.Value() })
+	mustPanicWith(t, ErrConcurrentModification, func() { it.SetValue(0) })
+	// Replacing a value is no structural change
+	it = m.Begin()
+	m.Put("a", 10)
+	if it.Key() != "a" || it.Value() != 10 {
+		t.Fatal("an iterator went stale on a value update")
+	}
+
+	// A safe iterator on a removed entry moves on to the following one
+	// instead of reading or writing the removed entry
+	m.entries.safeIters = true
+	it = m.LowerBound("b")
+	m.Delete("b")
+	if it.Key() != "c" || !it.SetValue(30) {
+		t.Fatal("the iterator did not move past the removed entry")
+	}
+	if v, _ := m.Get("c"); v != 30 || m.Contains("b") {
+		t.Fatalf("SetValue wrote to the wrong entry: %v", m.Keys())
+	}
+	it = m.LowerBound("c")
+	m.Delete("c")
+	if it.SetValue(0) || it.Valid() {
+		t.Fatal("an iterator past the removed last entry took a value")
+	}
+}
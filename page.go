@@ -0,0 +1,72 @@
+package set
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// Cursor is an opaque, URL-safe position in a set for resuming a paged
+// scan across requests. The zero value starts at the beginning.
+type Cursor string
+
+// ErrInvalidCursor is returned by Page for a cursor it cannot decode
+var ErrInvalidCursor = errors.New("set: invalid page cursor")
+
+// Page returns up to limit elements in ascending order following the
+// position of cursor, and the cursor of the next page, which is empty
+// after the last page. A cursor records the last element returned rather
+// than an index, so elements inserted or removed between requests,
+// including that element itself, neither repeat nor skip the others. The
+// element is gob-encoded into the cursor.
+func (s *Set[T]) Page(cursor Cursor, limit int) ([]T, Cursor, error) {
+	var node *Node[T]
+	if cursor == "" {
+		if s.root != nil {
+			node = s.minimum(s.root)
+		}
+	} else {
+		data, err := base64.RawURLEncoding.DecodeString(string(cursor))
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		var after T
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&after); err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		node = s.upperBound(s.canonical(after))
+	}
+	items, more := s.page(node, limit)
+	if !more {
+		return items, "", nil
+	}
+	if len(items) == 0 {
+		return items, cursor, nil
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(items[len(items)-1]); err != nil {
+		return nil, "", err
+	}
+	return items, Cursor(base64.RawURLEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// PageAfter returns up to limit elements greater than after in ascending
+// order, and whether more elements follow. Passing the last element of a
+// page fetches the next one.
+func (s *Set[T]) PageAfter(after T, limit int) ([]T, bool) {
+	return s.page(s.upperBound(s.canonical(after)), limit)
+}
+
+// page collects up to limit elements starting at node
+func (s *Set[T]) page(node *Node[T], limit int) ([]T, bool) {
+	var items []T
+	if limit > 0 {
+		items = make([]T, 0, min(limit, s.size))
+	}
+	for ; node != nil && len(items) < limit; node = s.successor(node) {
+		items = append(items, node.key)
+	}
+	return items, node != nil
+}
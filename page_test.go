@@ -0,0 +1,62 @@
+package set
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPage(t *testing.T) {
+	s := intSet()
+	for i := 0; i < 10; i++ {
+		s.Insert(i)
+	}
+	var got []int
+	var cursor Cursor
+	pages := 0
+	for {
+		items, next, err := s.Page(cursor, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, items...)
+		pages++
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if pages != 4 || !slices.Equal(got, s.ToSlice()) {
+		t.Fatalf("%d pages gave %v", pages, got)
+	}
+}
+
+func TestPageAcrossChanges(t *testing.T) {
+	s := intSet(1, 2, 3, 4, 5, 6)
+	items, cursor, _ := s.Page("", 3)
+	if !slices.Equal(items, []int{1, 2, 3}) {
+		t.Fatalf("first page = %v", items)
+	}
+	// Removing the last element returned and inserting before the cursor
+	// neither repeats nor skips anything
+	s.Remove(3)
+	s.Insert(0)
+	s.Insert(7)
+	items, cursor, _ = s.Page(cursor, 10)
+	if !slices.Equal(items, []int{4, 5, 6, 7}) || cursor != "" {
+		t.Fatalf("second page = %v, cursor %q", items, cursor)
+	}
+	if _, _, err := s.Page("not a cursor!", 3); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("a malformed cursor: %v", err)
+	}
+}
+
+func TestPageAfter(t *testing.T) {
+	s := intSet(1, 3, 5, 7)
+	if items, more := s.PageAfter(3, 1); !slices.Equal(items, []int{5}) || !more {
+		t.Fatalf("PageAfter(3, 1) = %v, %v", items, more)
+	}
+	if items, more := s.PageAfter(4, 5); !slices.Equal(items, []int{5, 7}) || more {
+		t.Fatalf("PageAfter(4, 5) = %v, %v", items, more)
+	}
+}
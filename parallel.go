@@ -0,0 +1,66 @@
+package set
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelForEach calls fn for every element from up to workers
+// goroutines, in no particular order, and returns once all calls are done.
+// The tree is cut into disjoint subtrees of at most a quarter of an even
+// share each, which the workers take in turn, so they stay busy even when
+// fn is slower for some elements than for others. The elements above the
+// cut are visited by the calling goroutine. fn must be safe for concurrent
+// use and must not modify the set. A workers value below 1 means
+// GOMAXPROCS.
+func (s *Set[T]) ParallelForEach(fn func(key T), workers int) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	visit := func(key T) bool {
+		fn(key)
+		return true
+	}
+	if workers == 1 || s.size < 2*workers {
+		ascend(s.root, visit)
+		return
+	}
+
+	limit := max(s.size/(4*workers), 1)
+	var subtrees []*Node[T]
+	var tops []T
+	var cut func(node *Node[T])
+	cut = func(node *Node[T]) {
+		if node == nil {
+			return
+		}
+		if node.size <= limit {
+			subtrees = append(subtrees, node)
+			return
+		}
+		tops = append(tops, node.key)
+		cut(node.left)
+		cut(node.right)
+	}
+	cut(s.root)
+
+	work := make(chan *Node[T], len(subtrees))
+	for _, node := range subtrees {
+		work <- node
+	}
+	close(work)
+	var wg sync.WaitGroup
+	for i := 0; i < min(workers, len(subtrees)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for node := range work {
+				ascend(node, visit)
+			}
+		}()
+	}
+	for _, key := range tops {
+		fn(key)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,39 @@
+package set
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelForEach(t *testing.T) {
+	s := intSet()
+	for i := 0; i < 5000; i++ {
+		s.Insert(i)
+	}
+	for _, workers := range []int{0, 1, 3, 8} {
+		var mu sync.Mutex
+		seen := make([]int, s.Size())
+		var calls atomic.Int64
+		s.ParallelForEach(func(key int) {
+			calls.Add(1)
+			mu.Lock()
+			seen[key]++
+			mu.Unlock()
+		}, workers)
+		if calls.Load() != int64(s.Size()) {
+			t.Fatalf("%d workers made %d calls for %d elements", workers, calls.Load(), s.Size())
+		}
+		for key, n := range seen {
+			if n != 1 {
+				t.Fatalf("%d workers visited %d %d times", workers, key, n)
+			}
+		}
+	}
+	// Sets too small to split are walked by the caller
+	n := 0
+	intSet(1, 2, 3).ParallelForEach(func(int) { n++ }, 4)
+	if n != 3 {
+		t.Fatalf("visited %d of 3 elements", n)
+	}
+}
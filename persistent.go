@@ -0,0 +1,324 @@
+package set
+
+// PersistentSet is an immutable set. Insert and Remove return a new set
+// that shares all unchanged subtrees with the old one, copying only the
+// O(log n) nodes on the path to the change, so every version stays valid
+// and can be read from any number of goroutines without locking.
+//
+// The tree is a red-black tree without parent pointers, rebalanced with
+// Okasaki's insertion and Kahrs' deletion.
+type PersistentSet[T any] struct {
+	root    *pnode[T]
+	compare func(T, T) int
+}
+
+type pnode[T any] struct {
+	key         T
+	color       Color
+	left, right *pnode[T]
+	size        int
+}
+
+// NewPersistentSet creates a new empty persistent set with a custom
+// comparator
+func NewPersistentSet[T any](compare func(T, T) int) *PersistentSet[T] {
+	requireComparator(compare)
+	return &PersistentSet[T]{compare: compare}
+}
+
+// Size returns the number of elements in the set
+func (p *PersistentSet[T]) Size() int {
+	return psize(p.root)
+}
+
+// IsEmpty returns true if the set has no elements
+func (p *PersistentSet[T]) IsEmpty() bool {
+	return p.root == nil
+}
+
+// Contains checks if an element exists in the set
+func (p *PersistentSet[T]) Contains(key T) bool {
+	_, ok := p.Find(key)
+	return ok
+}
+
+// Find returns the element stored in the set that is equal to key
+func (p *PersistentSet[T]) Find(key T) (T, bool) {
+	node := p.root
+	for node != nil {
+		cmp := p.compare(key, node.key)
+		if cmp == 0 {
+			return node.key, true
+		} else if cmp < 0 {
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Insert returns a set that also holds key. If key is already present the
+// receiver itself is returned.
+func (p *PersistentSet[T]) Insert(key T) *PersistentSet[T] {
+	if p.Contains(key) {
+		return p
+	}
+	return &PersistentSet[T]{root: pblacken(p.ins(p.root, key)), compare: p.compare}
+}
+
+// Remove returns a set without key. If key is not present the receiver
+// itself is returned.
+func (p *PersistentSet[T]) Remove(key T) *PersistentSet[T] {
+	// Deletion assumes the key is present, since it rebalances for a
+	// subtree that lost a black node on the way back up
+	if !p.Contains(key) {
+		return p
+	}
+	return &PersistentSet[T]{root: pblacken(p.del(p.root, key)), compare: p.compare}
+}
+
+// Min returns the smallest element
+func (p *PersistentSet[T]) Min() (T, bool) {
+	var zero T
+	if p.root == nil {
+		return zero, false
+	}
+	node := p.root
+	for node.left != nil {
+		node = node.left
+	}
+	return node.key, true
+}
+
+// Max returns the largest element
+func (p *PersistentSet[T]) Max() (T, bool) {
+	var zero T
+	if p.root == nil {
+		return zero, false
+	}
+	node := p.root
+	for node.right != nil {
+		node = node.right
+	}
+	return node.key, true
+}
+
+// At returns the element at index i in ascending order, or false if i is
+// out of range
+func (p *PersistentSet[T]) At(i int) (T, bool) {
+	node := p.root
+	for node != nil {
+		left := psize(node.left)
+		switch {
+		case i < left:
+			node = node.left
+		case i > left:
+			i -= left + 1
+			node = node.right
+		default:
+			return node.key, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Rank returns the number of elements less than key
+func (p *PersistentSet[T]) Rank(key T) int {
+	rank := 0
+	node := p.root
+	for node != nil {
+		if p.compare(key, node.key) <= 0 {
+			node = node.left
+		} else {
+			rank += psize(node.left) + 1
+			node = node.right
+		}
+	}
+	return rank
+}
+
+// Ascend calls fn for every element in ascending order until fn returns
+// false
+func (p *PersistentSet[T]) Ascend(fn func(key T) bool) {
+	pascend(p.root, fn)
+}
+
+// ToSlice returns the elements in ascending order
+func (p *PersistentSet[T]) ToSlice() []T {
+	keys := make([]T, 0, p.Size())
+	p.Ascend(func(key T) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+func pascend[T any](node *pnode[T], fn func(T) bool) bool {
+	for node != nil {
+		if !pascend(node.left, fn) || !fn(node.key) {
+			return false
+		}
+		node = node.right
+	}
+	return true
+}
+
+func (p *PersistentSet[T]) ins(t *pnode[T], key T) *pnode[T] {
+	if t == nil {
+		return pmake(Red, nil, key, nil)
+	}
+	if p.compare(key, t.key) < 0 {
+		if t.color == Black {
+			return pbalance(p.ins(t.left, key), t.key, t.right)
+		}
+		return pmake(Red, p.ins(t.left, key), t.key, t.right)
+	}
+	if t.color == Black {
+		return pbalance(t.left, t.key, p.ins(t.right, key))
+	}
+	return pmake(Red, t.left, t.key, p.ins(t.right, key))
+}
+
+func (p *PersistentSet[T]) del(t *pnode[T], key T) *pnode[T] {
+	if t == nil {
+		return nil
+	}
+	switch cmp := p.compare(key, t.key); {
+	case cmp < 0:
+		if isBlack(t.left) {
+			return pbalanceLeft(p.del(t.left, key), t.key, t.right)
+		}
+		return pmake(Red, p.del(t.left, key), t.key, t.right)
+	case cmp > 0:
+		if isBlack(t.right) {
+			return pbalanceRight(t.left, t.key, p.del(t.right, key))
+		}
+		return pmake(Red, t.left, t.key, p.del(t.right, key))
+	}
+	return pappend(t.left, t.right)
+}
+
+// pmake returns a new node, computing its subtree size
+func pmake[T any](color Color, left *pnode[T], key T, right *pnode[T]) *pnode[T] {
+	return &pnode[T]{
+		key:   key,
+		color: color,
+		left:  left,
+		right: right,
+		size:  psize(left) + psize(right) + 1,
+	}
+}
+
+func psize[T any](node *pnode[T]) int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
+
+func isRed[T any](node *pnode[T]) bool {
+	return node != nil && node.color == Red
+}
+
+func isBlack[T any](node *pnode[T]) bool {
+	return node != nil && node.color == Black
+}
+
+// pblacken returns node colored black, copying it if it is red
+func pblacken[T any](node *pnode[T]) *pnode[T] {
+	if isRed(node) {
+		return pmake(Black, node.left, node.key, node.right)
+	}
+	return node
+}
+
+// pbalance builds a black node from left, key and right, resolving a red
+// node with a red child directly below it into a red node with two black
+// children
+func pbalance[T any](left *pnode[T], key T, right *pnode[T]) *pnode[T] {
+	switch {
+	case isRed(left) && isRed(right):
+		return pmake(Red, pmake(Black, left.left, left.key, left.right), key,
+			pmake(Black, right.left, right.key, right.right))
+	case isRed(left) && isRed(left.left):
+		return pmake(Red, pmake(Black, left.left.left, left.left.key, left.left.right), left.key,
+			pmake(Black, left.right, key, right))
+	case isRed(left) && isRed(left.right):
+		return pmake(Red, pmake(Black, left.left, left.key, left.right.left), left.right.key,
+			pmake(Black, left.right.right, key, right))
+	case isRed(right) && isRed(right.right):
+		return pmake(Red, pmake(Black, left, key, right.left), right.key,
+			pmake(Black, right.right.left, right.right.key, right.right.right))
+	case isRed(right) && isRed(right.left):
+		return pmake(Red, pmake(Black, left, key, right.left.left), right.left.key,
+			pmake(Black, right.left.right, right.key, right.right))
+	}
+	return pmake(Black, left, key, right)
+}
+
+// pbalanceLeft rebuilds a node whose left subtree lost one black node
+func pbalanceLeft[T any](left *pnode[T], key T, right *pnode[T]) *pnode[T] {
+	switch {
+	case isRed(left):
+		return pmake(Red, pmake(Black, left.left, left.key, left.right), key, right)
+	case isBlack(right):
+		return pbalance(left, key, pmake(Red, right.left, right.key, right.right))
+	case isRed(right) && isBlack(right.left):
+		return pmake(Red, pmake(Black, left, key, right.left.left), right.left.key,
+			pbalance(right.left.right, right.key, pdemote(right.right)))
+	}
+	panic("set: persistent tree is unbalanced")
+}
+
+// pbalanceRight rebuilds a node whose right subtree lost one black node
+func pbalanceRight[T any](left *pnode[T], key T, right *pnode[T]) *pnode[T] {
+	switch {
+	case isRed(right):
+		return pmake(Red, left, key, pmake(Black, right.left, right.key, right.right))
+	case isBlack(left):
+		return pbalance(pmake(Red, left.left, left.key, left.right), key, right)
+	case isRed(left) && isBlack(left.right):
+		return pmake(Red, pbalance(pdemote(left.left), left.key, left.right.left), left.right.key,
+			pmake(Black, left.right.right, key, right))
+	}
+	panic("set: persistent tree is unbalanced")
+}
+
+// pdemote returns a red copy of a black node, lowering its black height
+func pdemote[T any](node *pnode[T]) *pnode[T] {
+	if !isBlack(node) {
+		panic("set: persistent tree is unbalanced")
+	}
+	return pmake(Red, node.left, node.key, node.right)
+}
+
+// pappend joins the children of a deleted node, all of whose keys in left
+// are less than those in right
+func pappend[T any](left, right *pnode[T]) *pnode[T] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case isRed(left) && isRed(right):
+		mid := pappend(left.right, right.left)
+		if isRed(mid) {
+			return pmake(Red, pmake(Red, left.left, left.key, mid.left), mid.key,
+				pmake(Red, mid.right, right.key, right.right))
+		}
+		return pmake(Red, left.left, left.key, pmake(Red, mid, right.key, right.right))
+	case isBlack(left) && isBlack(right):
+		mid := pappend(left.right, right.left)
+		if isRed(mid) {
+			return pmake(Red, pmake(Black, left.left, left.key, mid.left), mid.key,
+				pmake(Black, mid.right, right.key, right.right))
+		}
+		return pbalanceLeft(left.left, left.key, pmake(Black, mid, right.key, right.right))
+	case isRed(right):
+		return pmake(Red, pappend(left, right.left), right.key, right.right)
+	}
+	return pmake(Red, left.left, left.key, pappend(left.right, right))
+}
@@ -0,0 +1,117 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkPersistent fails t unless the tree of p is a valid red-black tree
+// with consistent subtree sizes
+func checkPersistent(t *testing.T, p *PersistentSet[int]) {
+	t.Helper()
+	if isRed(p.root) {
+		t.Fatal("persistent root is red")
+	}
+	var walk func(node *pnode[int]) int
+	walk = func(node *pnode[int]) int {
+		if node == nil {
+			return 1
+		}
+		if isRed(node) && (isRed(node.left) || isRed(node.right)) {
+			t.Fatalf("red key %d has a red child", node.key)
+		}
+		if node.size != psize(node.left)+psize(node.right)+1 {
+			t.Fatalf("key %d has subtree size %d", node.key, node.size)
+		}
+		left, right := walk(node.left), walk(node.right)
+		if left != right {
+			t.Fatalf("key %d has black heights %d and %d", node.key, left, right)
+		}
+		if node.color == Black {
+			left++
+		}
+		return left
+	}
+	walk(p.root)
+	if keys := p.ToSlice(); !slices.IsSorted(keys) {
+		t.Fatalf("persistent keys out of order: %v", keys)
+	}
+}
+
+func TestPersistentVersions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var versions []*PersistentSet[int]
+	var contents [][]int
+	p := NewPersistentSet(cmp.Compare[int])
+	ref := NewSet(cmp.Compare[int])
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(300)
+		if rng.Intn(2) == 0 {
+			p = p.Remove(key)
+			ref.Remove(key)
+		} else {
+			p = p.Insert(key)
+			ref.Insert(key)
+		}
+		if i%100 == 0 {
+			checkPersistent(t, p)
+			versions = append(versions, p)
+			contents = append(contents, ref.ToSlice())
+		}
+	}
+	for i, v := range versions {
+		if got := v.ToSlice(); !slices.Equal(got, contents[i]) {
+			t.Fatalf("version %d changed to %v, want %v", i, got, contents[i])
+		}
+	}
+}
+
+func TestPersistentDelete(t *testing.T) {
+	// Deleting every key in random order exercises each of Kahrs'
+	// rebalancing cases
+	for seed := int64(0); seed < 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		p := NewPersistentSet(cmp.Compare[int])
+		for _, key := range rng.Perm(200) {
+			p = p.Insert(key)
+		}
+		for i, key := range rng.Perm(200) {
+			before := p
+			p = p.Remove(key)
+			if p.Contains(key) || p.Size() != 199-i {
+				t.Fatalf("Remove(%d) left size %d", key, p.Size())
+			}
+			if !before.Contains(key) {
+				t.Fatalf("Remove(%d) changed the previous version", key)
+			}
+			checkPersistent(t, p)
+		}
+	}
+}
+
+func TestPersistentMissing(t *testing.T) {
+	p := NewPersistentSet(cmp.Compare[int]).Insert(1).Insert(2)
+	if q := p.Remove(3); q.Size() != 2 || !q.Contains(1) || !q.Contains(2) {
+		t.Fatalf("removing a missing key gave %v", q.ToSlice())
+	}
+}
+
+func TestPersistentOrderStatistics(t *testing.T) {
+	p := NewPersistentSet(cmp.Compare[int])
+	for i := 0; i < 100; i++ {
+		p = p.Insert(2 * i)
+	}
+	for i := 0; i < 100; i++ {
+		if key, ok := p.At(i); !ok || key != 2*i {
+			t.Fatalf("At(%d) = %d, %v", i, key, ok)
+		}
+		if rank := p.Rank(2*i + 1); rank != i+1 {
+			t.Fatalf("Rank(%d) = %d, want %d", 2*i+1, rank, i+1)
+		}
+	}
+	if _, ok := p.At(100); ok {
+		t.Fatal("At past the end succeeded")
+	}
+}
@@ -0,0 +1,107 @@
+package set
+
+import "sync"
+
+// nodeAllocator supplies the nodes of a set and takes back removed ones.
+// Nodes returned by alloc may hold stale fields, which the caller
+// overwrites.
+type nodeAllocator[T any] interface {
+	alloc() *Node[T]
+	free(node *Node[T])
+	// reset is called when the set drops all of its nodes at once
+	reset()
+}
+
+// WithNodePool makes the set recycle the nodes of removed elements for
+// later inserts through a sync.Pool, which relieves the garbage collector
+// under heavy insert and remove churn. Nodes dropped by Clear are left to
+// the garbage collector.
+func WithNodePool[T any]() Option[T] {
+	return func(s *Set[T]) {
+		s.nodes = &poolAllocator[T]{}
+	}
+}
+
+type poolAllocator[T any] struct {
+	pool sync.Pool
+}
+
+func (p *poolAllocator[T]) alloc() *Node[T] {
+	if node, ok := p.pool.Get().(*Node[T]); ok {
+		return node
+	}
+	return &Node[T]{}
+}
+
+func (p *poolAllocator[T]) reset() {}
+
+func (p *poolAllocator[T]) free(node *Node[T]) {
+	// Clearing the node drops its references to the key and the tree, so
+	// a pooled node keeps nothing else alive
+	*node = Node[T]{}
+	p.pool.Put(node)
+}
+
+// Arena slabs start small and double up to maxSlab nodes
+const (
+	minSlab = 32
+	maxSlab = 8192
+)
+
+// NewArenaSet creates a new set whose nodes are carved out of large
+// contiguous slabs instead of being allocated one by one. Huge sets get
+// far fewer heap objects and better locality; removed nodes are reused by
+// later inserts, and Clear releases all slabs at once.
+func NewArenaSet[T any](compare func(T, T) int, opts ...Option[T]) *Set[T] {
+	s := NewSetWithOptions(compare, opts...)
+	s.nodes = &arenaAllocator[T]{}
+	return s
+}
+
+// WithInitialCapacity allocates the nodes for n elements up front in a
+// single slab, so filling the set to that size allocates nothing more.
+// The set then carves its nodes from slabs as NewArenaSet does; Clear
+// releases the slab like any other.
+func WithInitialCapacity[T any](n int) Option[T] {
+	return func(s *Set[T]) {
+		a := &arenaAllocator[T]{}
+		if n > 0 {
+			a.slab = make([]Node[T], n)
+			a.slabSize = n
+		}
+		s.nodes = a
+	}
+}
+
+type arenaAllocator[T any] struct {
+	slab     []Node[T]
+	slabSize int      // length of the last slab allocated
+	freeList *Node[T] // linked through the parent field
+}
+
+func (a *arenaAllocator[T]) alloc() *Node[T] {
+	if node := a.freeList; node != nil {
+		a.freeList = node.parent
+		return node
+	}
+	if len(a.slab) == 0 {
+		a.slabSize = min(max(2*a.slabSize, minSlab), maxSlab)
+		a.slab = make([]Node[T], a.slabSize)
+	}
+	node := &a.slab[0]
+	a.slab = a.slab[1:]
+	return node
+}
+
+func (a *arenaAllocator[T]) free(node *Node[T]) {
+	*node = Node[T]{parent: a.freeList}
+	a.freeList = node
+}
+
+// reset forgets all slabs rather than reusing them, since snapshots may
+// still refer to their nodes
+func (a *arenaAllocator[T]) reset() {
+	a.slab = nil
+	a.slabSize = 0
+	a.freeList = nil
+}
@@ -0,0 +1,120 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSafeIteratorRecycledNode(t *testing.T) {
+	// The node of a removed element is reused by the next insert; a safe
+	// iterator still on it must not pick up the new element
+	s := NewArenaSet(cmp.Compare[int], WithSafeIterators[int]())
+	for i := 0; i < 10; i++ {
+		s.Insert(10 * i)
+	}
+	it := s.LowerBound(40)
+	node := it.node
+	s.Remove(40)
+	s.Insert(75)
+	if s.find(75) != node {
+		t.Fatal("the removed node was not recycled")
+	}
+	var got []int
+	for ; it.Valid(); it.Next() {
+		got = append(got, it.Value())
+	}
+	if want := []int{50, 60, 70, 75, 80, 90}; !slices.Equal(got, want) {
+		t.Fatalf("iterator saw %v, want %v", got, want)
+	}
+}
+
+func TestFailFastIteratorRecycledNode(t *testing.T) {
+	s := NewArenaSet(cmp.Compare[int])
+	for i := 0; i < 10; i++ {
+		s.Insert(i)
+	}
+	it := s.LowerBound(4)
+	s.Remove(4)
+	s.Insert(40)
+	defer func() {
+		if r := recover(); r != ErrConcurrentModification {
+			t.Fatalf("Value after a recycle: recovered %v", r)
+		}
+	}()
+	it.Value()
+}
+
+func TestNodePool(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := NewSetWithOptions(cmp.Compare[int], WithNodePool[int]())
+	want := map[int]bool{}
+	for round := 0; round < 20; round++ {
+		randomOps(rng, s, want, 500, 200)
+		checkSet(t, s, want)
+	}
+}
+
+func TestArena(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	s := NewArenaSet(cmp.Compare[int])
+	want := map[int]bool{}
+	for round := 0; round < 20; round++ {
+		randomOps(rng, s, want, 500, 200)
+		checkSet(t, s, want)
+	}
+	s.Clear()
+	checkSet(t, s, map[int]bool{})
+	s.Insert(1)
+	checkSet(t, s, map[int]bool{1: true})
+}
+
+func TestArenaSlabsDouble(t *testing.T) {
+	s := NewArenaSet(cmp.Compare[int])
+	a := s.nodes.(*arenaAllocator[int])
+	var sizes []int
+	for i := 0; i < 1000; i++ {
+		s.Insert(i)
+		if len(sizes) == 0 || sizes[len(sizes)-1] != a.slabSize {
+			sizes = append(sizes, a.slabSize)
+		}
+	}
+	want := []int{32, 64, 128, 256, 512}
+	if len(sizes) != len(want)+1 || sizes[len(sizes)-1] != 1024 {
+		t.Fatalf("slab sizes %v, want %v then 1024", sizes, want)
+	}
+	for i, size := range want {
+		if sizes[i] != size {
+			t.Fatalf("slab sizes %v, want %v then 1024", sizes, want)
+		}
+	}
+}
+
+func TestArenaReusesNodes(t *testing.T) {
+	s := NewArenaSet(cmp.Compare[int])
+	for i := 0; i < 10; i++ {
+		s.Insert(i)
+	}
+	node := s.find(5)
+	s.Remove(5)
+	s.Insert(50)
+	if s.find(50) != node {
+		t.Fatal("insert after a removal did not reuse the removed node")
+	}
+}
+
+func TestPooledNodesAndSnapshots(t *testing.T) {
+	// Nodes a snapshot can still see must never be recycled
+	rng := rand.New(rand.NewSource(3))
+	s := NewArenaSet(cmp.Compare[int])
+	want := map[int]bool{}
+	randomOps(rng, s, want, 300, 100)
+	view := s.Snapshot()
+	frozen := view.ToSlice()
+	randomOps(rng, s, want, 2000, 100)
+	checkSet(t, s, want)
+	if got := view.ToSlice(); !slices.Equal(got, frozen) {
+		t.Fatalf("snapshot changed from %v to %v", frozen, got)
+	}
+}
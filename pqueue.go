@@ -0,0 +1,87 @@
+package set
+
+// PriorityQueue is an indexed priority queue over a set: elements are
+// unique, Pop returns the smallest one, and any element can be looked up,
+// removed or changed in O(log n) time, which container/heap cannot do
+// without an index of its own. Order by Reverse of a comparator for a
+// max-queue.
+type PriorityQueue[T any] struct {
+	set *Set[T]
+}
+
+// NewPriorityQueue creates a new empty priority queue with a custom
+// comparator
+func NewPriorityQueue[T any](compare func(T, T) int, opts ...Option[T]) *PriorityQueue[T] {
+	return &PriorityQueue[T]{set: NewSetWithOptions(compare, opts...)}
+}
+
+// Len returns the number of elements in the queue
+func (q *PriorityQueue[T]) Len() int {
+	return q.set.Size()
+}
+
+// IsEmpty returns true if the queue has no elements
+func (q *PriorityQueue[T]) IsEmpty() bool {
+	return q.set.IsEmpty()
+}
+
+// Push adds item to the queue and reports whether it was added, which it
+// is not if an equal element is queued
+func (q *PriorityQueue[T]) Push(item T) bool {
+	return q.set.Insert(item)
+}
+
+// Peek returns the smallest element without removing it
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	return q.set.Min()
+}
+
+// Pop removes and returns the smallest element
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	return q.set.PopMin()
+}
+
+// Contains checks if an element equal to item is queued
+func (q *PriorityQueue[T]) Contains(item T) bool {
+	return q.set.Contains(item)
+}
+
+// Remove removes the element equal to item from the queue
+func (q *PriorityQueue[T]) Remove(item T) bool {
+	return q.set.Remove(item)
+}
+
+// Update replaces the queued element equal to old with new, moving it to
+// its new position, and reports whether it did. Nothing changes if old is
+// not queued or if an element equal to new is queued other than old
+// itself; new equal to old just replaces the stored element.
+func (q *PriorityQueue[T]) Update(old, new T) bool {
+	s := q.set
+	s.checkMutable()
+	node := s.find(s.canonical(old))
+	if node == nil {
+		return false
+	}
+	if s.compare(node.key, s.canonical(new)) == 0 {
+		s.Replace(new)
+		return true
+	}
+	if s.find(s.canonical(new)) != nil {
+		return false
+	}
+	s.removeNode(node)
+	s.insert(s.canonical(new))
+	return true
+}
+
+// Clear removes all elements from the queue
+func (q *PriorityQueue[T]) Clear() {
+	q.set.Clear()
+}
+
+// Drain removes and returns all elements in priority order
+func (q *PriorityQueue[T]) Drain() []T {
+	items := q.set.ToSlice()
+	q.set.Clear()
+	return items
+}
@@ -0,0 +1,104 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue(cmp.Compare[int])
+	if _, ok := q.Pop(); ok {
+		t.Fatal("Pop from an empty queue succeeded")
+	}
+	for _, p := range []int{5, 1, 4} {
+		q.Push(p)
+	}
+	if q.Push(4) || q.Len() != 3 {
+		t.Fatal("a duplicate was queued")
+	}
+	if top, _ := q.Peek(); top != 1 || q.Len() != 3 {
+		t.Fatal("Peek did not return the smallest element in place")
+	}
+	if top, _ := q.Pop(); top != 1 || q.Contains(1) {
+		t.Fatal("Pop did not remove the smallest element")
+	}
+	if !q.Remove(5) || q.Remove(5) {
+		t.Fatal("Remove misreported")
+	}
+	if got := q.Drain(); !slices.Equal(got, []int{4}) || !q.IsEmpty() {
+		t.Fatalf("Drain = %v", got)
+	}
+
+	max := NewPriorityQueue(Reverse(cmp.Compare[int]))
+	max.Push(1)
+	max.Push(3)
+	if top, _ := max.Pop(); top != 3 {
+		t.Fatalf("a max-queue popped %d", top)
+	}
+}
+
+type job struct {
+	priority int
+	name     string
+}
+
+func compareJobs(a, b job) int {
+	if c := cmp.Compare(a.priority, b.priority); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.name, b.name)
+}
+
+func TestPriorityQueueUpdate(t *testing.T) {
+	q := NewPriorityQueue(compareJobs)
+	q.Push(job{3, "build"})
+	q.Push(job{5, "lint"})
+	q.Push(job{7, "deploy"})
+	if !q.Update(job{7, "deploy"}, job{1, "deploy"}) {
+		t.Fatal("Update of a queued job failed")
+	}
+	if top, _ := q.Peek(); top != (job{1, "deploy"}) || q.Contains(job{7, "deploy"}) {
+		t.Fatalf("the updated job is not at the front: %v", top)
+	}
+	// Neither a missing job nor a collision with another one is updated
+	if q.Update(job{9, "test"}, job{2, "test"}) || q.Update(job{3, "build"}, job{5, "lint"}) {
+		t.Fatal("an invalid Update succeeded")
+	}
+	if !q.Update(job{3, "build"}, job{3, "build"}) || q.Len() != 3 || q.set.Validate() != nil {
+		t.Fatal("updating a job to itself failed")
+	}
+}
+
+func TestPriorityQueueRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	q := NewPriorityQueue(cmp.Compare[int])
+	want := map[int]bool{}
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(500)
+		switch rng.Intn(3) {
+		case 0:
+			q.Push(key)
+			want[key] = true
+		case 1:
+			if top, ok := q.Pop(); ok {
+				for k := range want {
+					if k < top {
+						t.Fatalf("popped %d while %d is queued", top, k)
+					}
+				}
+				delete(want, top)
+			}
+		default:
+			old, _ := q.Peek()
+			if q.Update(old, key) {
+				delete(want, old)
+				want[key] = true
+			}
+		}
+	}
+	if q.Len() != len(want) || q.set.Validate() != nil {
+		t.Fatalf("queue holds %d elements, want %d", q.Len(), len(want))
+	}
+}
@@ -0,0 +1,126 @@
+package set
+
+import "math"
+
+// QuantileSketch is a bounded-memory summary of a stream of observations
+// that answers quantile queries with a rank error of at most epsilon·n,
+// following the Greenwald-Khanna algorithm. Only the tuples needed to meet
+// the error bound are retained, kept in order in a Set.
+type QuantileSketch[T any] struct {
+	compare  func(T, T) int
+	epsilon  float64
+	tuples   *Set[*gkTuple[T]]
+	count    int
+	seq      uint64
+	interval int
+}
+
+// gkTuple covers g observations ending at value; delta bounds the
+// uncertainty of its rank
+type gkTuple[T any] struct {
+	value T
+	seq   uint64
+	g     int
+	delta int
+}
+
+// NewQuantileSketch creates a sketch ordering observations with compare and
+// answering quantiles within epsilon, e.g. 0.01 for one percent
+func NewQuantileSketch[T any](compare func(T, T) int, epsilon float64) *QuantileSketch[T] {
+	requireComparator(compare)
+	q := &QuantileSketch[T]{
+		compare:  compare,
+		epsilon:  epsilon,
+		interval: int(math.Floor(1 / (2 * epsilon))),
+	}
+	if q.interval < 1 {
+		q.interval = 1
+	}
+	q.tuples = NewSet(func(ta, tb *gkTuple[T]) int {
+		if cmp := compare(ta.value, tb.value); cmp != 0 {
+			return cmp
+		}
+		switch {
+		case ta.seq < tb.seq:
+			return -1
+		case ta.seq > tb.seq:
+			return 1
+		}
+		return 0
+	})
+	return q
+}
+
+// Count returns the number of observations inserted
+func (q *QuantileSketch[T]) Count() int {
+	return q.count
+}
+
+// Retained returns the number of tuples currently kept by the sketch
+func (q *QuantileSketch[T]) Retained() int {
+	return q.tuples.Size()
+}
+
+// Insert records an observation
+func (q *QuantileSketch[T]) Insert(value T) {
+	q.seq++
+	t := &gkTuple[T]{value: value, seq: q.seq, g: 1}
+	q.tuples.Insert(t)
+	node := q.tuples.find(t)
+	next := q.tuples.successor(node)
+	if next != nil && q.tuples.predecessor(node) != nil {
+		succ := next.key
+		t.delta = succ.g + succ.delta - 1
+	}
+	q.count++
+	if q.count%q.interval == 0 {
+		q.compress()
+	}
+}
+
+// compress merges adjacent tuples whose combined rank uncertainty still
+// fits the error bound. The first and last tuples hold the exact minimum
+// and maximum and are never merged away.
+func (q *QuantileSketch[T]) compress() {
+	if q.tuples.Size() < 3 {
+		return
+	}
+	limit := int(math.Floor(2 * q.epsilon * float64(q.count)))
+	tuples := make([]*gkTuple[T], 0, q.tuples.Size())
+	for it := q.tuples.Begin(); it.Valid(); it.Next() {
+		tuples = append(tuples, it.Value())
+	}
+	for i := len(tuples) - 2; i >= 1; i-- {
+		cur, next := tuples[i], tuples[i+1]
+		if cur.g+next.g+next.delta <= limit {
+			next.g += cur.g
+			q.tuples.Remove(cur)
+			tuples[i] = next
+		}
+	}
+}
+
+// Query returns an observation whose rank is within epsilon·n of phi·n,
+// for phi in [0, 1]. It returns false if nothing was inserted.
+func (q *QuantileSketch[T]) Query(phi float64) (T, bool) {
+	var last T
+	if q.count == 0 {
+		return last, false
+	}
+	rank := int(math.Ceil(phi * float64(q.count)))
+	if rank < 1 {
+		rank = 1
+	}
+	bound := q.epsilon * float64(q.count)
+	minRank := 0
+	for it := q.tuples.Begin(); it.Valid(); it.Next() {
+		t := it.Value()
+		minRank += t.g
+		maxRank := minRank + t.delta
+		if float64(rank-minRank) <= bound && float64(maxRank-rank) <= bound {
+			return t.value, true
+		}
+		last = t.value
+	}
+	return last, true
+}
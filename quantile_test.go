@@ -0,0 +1,84 @@
+package set
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkQuantiles fails t unless every answer of q for the values it saw,
+// sorted in sorted, is within the rank error of epsilon
+func checkQuantiles(t *testing.T, q *QuantileSketch[int], sorted []int, epsilon float64) {
+	t.Helper()
+	n := len(sorted)
+	bound := int(math.Ceil(epsilon * float64(n)))
+	for _, phi := range []float64{0, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 1} {
+		got, ok := q.Query(phi)
+		if !ok {
+			t.Fatalf("Query(%v) found nothing", phi)
+		}
+		// The ranks held by got, counted from 1
+		lo, _ := slices.BinarySearch(sorted, got)
+		hi, _ := slices.BinarySearch(sorted, got+1)
+		target := max(int(math.Ceil(phi*float64(n))), 1)
+		if target < lo+1-bound || target > hi+bound {
+			t.Fatalf("Query(%v) = %d with ranks %d..%d, want rank %d ± %d", phi, got, lo+1, hi, target, bound)
+		}
+	}
+}
+
+func TestQuantileSketch(t *testing.T) {
+	for _, epsilon := range []float64{0.1, 0.01, 0.001} {
+		rng := rand.New(rand.NewSource(1))
+		q := NewQuantileSketch(cmp.Compare[int], epsilon)
+		var values []int
+		for i := 0; i < 100000; i++ {
+			v := rng.Intn(1000000)
+			q.Insert(v)
+			values = append(values, v)
+		}
+		slices.Sort(values)
+		if q.Count() != len(values) {
+			t.Fatalf("Count() = %d, want %d", q.Count(), len(values))
+		}
+		checkQuantiles(t, q, values, epsilon)
+		if q.Retained() >= len(values)/5 {
+			t.Fatalf("epsilon %v retained %d of %d observations", epsilon, q.Retained(), len(values))
+		}
+	}
+}
+
+func TestQuantileSketchOrders(t *testing.T) {
+	// Sorted input and heavy duplicates are the hard cases for merging
+	// tuples
+	inputs := map[string]func(i int) int{
+		"ascending":  func(i int) int { return i },
+		"descending": func(i int) int { return 50000 - i },
+		"duplicates": func(i int) int { return i % 7 },
+	}
+	for name, value := range inputs {
+		q := NewQuantileSketch(cmp.Compare[int], 0.01)
+		var values []int
+		for i := 0; i < 50000; i++ {
+			q.Insert(value(i))
+			values = append(values, value(i))
+		}
+		slices.Sort(values)
+		t.Run(name, func(t *testing.T) {
+			checkQuantiles(t, q, values, 0.01)
+		})
+	}
+}
+
+func TestQuantileSketchEmpty(t *testing.T) {
+	q := NewQuantileSketch(cmp.Compare[int], 0.01)
+	if _, ok := q.Query(0.5); ok {
+		t.Fatal("Query on an empty sketch succeeded")
+	}
+	q.Insert(42)
+	if v, ok := q.Query(0.5); !ok || v != 42 {
+		t.Fatalf("Query on a single observation = %d, %v", v, ok)
+	}
+}
@@ -0,0 +1,130 @@
+package set
+
+import (
+	"fmt"
+	"io"
+)
+
+// Recorder wraps a set, logs every mutation made through it and checks the
+// tree invariants after each one. When a violation is detected it writes a
+// minimized sequence of operations reproducing it, formatted as Go test
+// code for this package.
+type Recorder[T any] struct {
+	set *Set[T]
+	out io.Writer
+	ops []recordedOp[T]
+	err error
+}
+
+type recordedOp[T any] struct {
+	remove bool
+	key    T
+}
+
+// NewRecorder starts recording mutations of s. Elements already in s are
+// recorded as ascending inserts, so recording is most precise when it
+// starts from an empty set.
+func NewRecorder[T any](s *Set[T], out io.Writer) *Recorder[T] {
+	r := &Recorder[T]{set: s, out: out}
+	for it := s.Begin(); it.Valid(); it.Next() {
+		r.ops = append(r.ops, recordedOp[T]{key: it.Value()})
+	}
+	return r
+}
+
+// Set returns the recorded set
+func (r *Recorder[T]) Set() *Set[T] {
+	return r.set
+}
+
+// Err returns the first invariant violation detected, if any
+func (r *Recorder[T]) Err() error {
+	return r.err
+}
+
+// Insert inserts key into the recorded set
+func (r *Recorder[T]) Insert(key T) bool {
+	ok := r.set.Insert(key)
+	r.record(recordedOp[T]{key: key})
+	return ok
+}
+
+// Remove removes key from the recorded set
+func (r *Recorder[T]) Remove(key T) bool {
+	ok := r.set.Remove(key)
+	r.record(recordedOp[T]{remove: true, key: key})
+	return ok
+}
+
+// Clear clears the recorded set and forgets the operations recorded so far
+func (r *Recorder[T]) Clear() {
+	r.set.Clear()
+	r.ops = r.ops[:0]
+}
+
+func (r *Recorder[T]) record(op recordedOp[T]) {
+	if r.err != nil {
+		return
+	}
+	r.ops = append(r.ops, op)
+	if err := r.set.Validate(); err != nil {
+		r.err = err
+		r.report(r.minimize())
+	}
+}
+
+// replay applies ops to a fresh set and returns the number of operations
+// after which the first violation occurred, or -1 if none did
+func (r *Recorder[T]) replay(ops []recordedOp[T]) int {
+	s := r.set.emptyCopy()
+	for i, op := range ops {
+		if op.remove {
+			s.Remove(op.key)
+		} else {
+			s.Insert(op.key)
+		}
+		if s.Validate() != nil {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// minimize shrinks the recorded operations to a short sequence that still
+// reproduces a violation, by repeatedly dropping chunks that aren't needed
+func (r *Recorder[T]) minimize() []recordedOp[T] {
+	ops := append([]recordedOp[T](nil), r.ops...)
+	n := r.replay(ops)
+	if n < 0 {
+		return ops
+	}
+	ops = ops[:n]
+	for chunk := len(ops) / 2; chunk >= 1; chunk /= 2 {
+		for start := 0; start+chunk <= len(ops); {
+			candidate := append(append([]recordedOp[T](nil), ops[:start]...), ops[start+chunk:]...)
+			if n := r.replay(candidate); n >= 0 {
+				ops = candidate[:n]
+			} else {
+				start += chunk
+			}
+		}
+	}
+	return ops
+}
+
+func (r *Recorder[T]) report(ops []recordedOp[T]) {
+	if r.out == nil {
+		return
+	}
+	fmt.Fprintf(r.out, "// %v\n", r.err)
+	fmt.Fprintf(r.out, "func TestReproduce(t *testing.T) {\n")
+	fmt.Fprintf(r.out, "\ts := NewSet(compare) // use the comparator of the failing set\n")
+	for _, op := range ops {
+		if op.remove {
+			fmt.Fprintf(r.out, "\ts.Remove(%#v)\n", op.key)
+		} else {
+			fmt.Fprintf(r.out, "\ts.Insert(%#v)\n", op.key)
+		}
+	}
+	fmt.Fprintf(r.out, "\tif err := s.Validate(); err != nil {\n\t\tt.Fatal(err)\n\t}\n}\n")
+}
@@ -0,0 +1,56 @@
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"strings"
+	"testing"
+)
+
+// brokenCompare claims 13 is greater than every other key and every other
+// key greater than 13, so 13 is misplaced as soon as a rotation moves it
+// below a larger key
+func brokenCompare(a, b int) int {
+	if a != b && (a == 13 || b == 13) {
+		return 1
+	}
+	return cmp.Compare(a, b)
+}
+
+func TestRecorder(t *testing.T) {
+	var out bytes.Buffer
+	r := NewRecorder(NewSet(brokenCompare), &out)
+	r.Insert(13)
+	for i := 30; i < 60 && r.Err() == nil; i++ {
+		r.Insert(i)
+		r.Remove(i - 10)
+	}
+	if r.Err() == nil {
+		t.Fatal("an inconsistent comparator went unnoticed")
+	}
+	report := out.String()
+	if !strings.Contains(report, "func TestReproduce(t *testing.T) {") || !strings.Contains(report, r.Err().Error()) {
+		t.Fatalf("report is missing the test or the error:\n%s", report)
+	}
+	// The minimized sequence is far shorter than everything recorded
+	if ops := strings.Count(report, "\ts.Insert(") + strings.Count(report, "\ts.Remove("); ops == 0 || ops >= len(r.ops) {
+		t.Fatalf("reproducer has %d of %d operations", ops, len(r.ops))
+	}
+}
+
+func TestRecorderValidSet(t *testing.T) {
+	var out bytes.Buffer
+	s := intSet(5)
+	r := NewRecorder(s, &out)
+	for i := 0; i < 100; i++ {
+		r.Insert(i)
+		r.Remove(i / 2)
+	}
+	if r.Err() != nil || out.Len() != 0 || r.Set() != s {
+		t.Fatalf("a valid set was reported: %v", r.Err())
+	}
+	r.Clear()
+	if len(r.ops) != 0 || !s.IsEmpty() {
+		t.Fatal("Clear kept the recorded operations")
+	}
+}
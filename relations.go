@@ -0,0 +1,87 @@
+package set
+
+// Compare orders s and other lexicographically by their elements, using the
+// comparator of s: the first differing element decides, and a set that is
+// a prefix of the other is smaller. It returns -1, 0 or 1.
+func (s *Set[T]) Compare(other *Set[T]) int {
+	a, b := s.first(), other.first()
+	for a != nil && b != nil {
+		if cmp := s.compare(a.key, b.key); cmp != 0 {
+			if cmp < 0 {
+				return -1
+			}
+			return 1
+		}
+		a, b = s.successor(a), other.successor(b)
+	}
+	switch {
+	case a != nil:
+		return 1
+	case b != nil:
+		return -1
+	}
+	return 0
+}
+
+// CompareSets orders two sets with Compare. It has the comparator
+// signature, so sets can themselves be elements of a Set.
+func CompareSets[T any](a, b *Set[T]) int {
+	return a.Compare(b)
+}
+
+// Equal returns true if s and other hold the same elements under the
+// comparator of s
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.size == other.size && s.Compare(other) == 0
+}
+
+// IsSubsetOf returns true if every element of s is in other. Both trees
+// are walked in order together, in O(n+m) time.
+func (s *Set[T]) IsSubsetOf(other *Set[T]) bool {
+	if s.size > other.size {
+		return false
+	}
+	a, b := s.first(), other.first()
+	for a != nil {
+		if b == nil {
+			return false
+		}
+		switch cmp := s.compare(a.key, b.key); {
+		case cmp < 0:
+			return false
+		case cmp == 0:
+			a = s.successor(a)
+		}
+		b = other.successor(b)
+	}
+	return true
+}
+
+// IsSupersetOf returns true if every element of other is in s
+func (s *Set[T]) IsSupersetOf(other *Set[T]) bool {
+	return other.IsSubsetOf(s)
+}
+
+// IsDisjointFrom returns true if s and other have no element in common
+func (s *Set[T]) IsDisjointFrom(other *Set[T]) bool {
+	a, b := s.first(), other.first()
+	for a != nil && b != nil {
+		switch cmp := s.compare(a.key, b.key); {
+		case cmp == 0:
+			return false
+		case cmp < 0:
+			a = s.successor(a)
+		default:
+			b = other.successor(b)
+		}
+	}
+	return true
+}
+
+// first returns the node holding the smallest element, or nil
+func (s *Set[T]) first() *Node[T] {
+	if s.root == nil {
+		return nil
+	}
+	return s.minimum(s.root)
+}
@@ -0,0 +1,63 @@
+package set
+
+import (
+	"testing"
+)
+
+func TestCompareSets(t *testing.T) {
+	for _, c := range []struct {
+		a, b []int
+		want int
+	}{
+		{nil, nil, 0},
+		{[]int{1, 2}, []int{1, 2}, 0},
+		{[]int{1, 2}, []int{1, 3}, -1},
+		{[]int{1, 2}, []int{1}, 1},
+		{nil, []int{0}, -1},
+		{[]int{5}, []int{1, 2, 3}, 1},
+	} {
+		a, b := intSet(c.a...), intSet(c.b...)
+		if got := a.Compare(b); got != c.want {
+			t.Errorf("%v.Compare(%v) = %d, want %d", c.a, c.b, got, c.want)
+		}
+		if got := CompareSets(b, a); got != -c.want {
+			t.Errorf("CompareSets(%v, %v) = %d, want %d", c.b, c.a, got, -c.want)
+		}
+	}
+	// Sets can be elements of a set
+	family := NewSet(CompareSets[int])
+	family.Insert(intSet(1, 2))
+	family.Insert(intSet(1))
+	if family.Insert(intSet(2, 1)) || family.Size() != 2 {
+		t.Fatal("an equal set was inserted twice")
+	}
+	if first, _ := family.Min(); first.Size() != 1 {
+		t.Fatal("the smaller set is not first")
+	}
+}
+
+func TestSetRelations(t *testing.T) {
+	for _, c := range []struct {
+		a, b                    []int
+		equal, subset, disjoint bool
+	}{
+		{nil, nil, true, true, true},
+		{nil, []int{1}, false, true, true},
+		{[]int{1, 2}, []int{1, 2}, true, true, false},
+		{[]int{1, 3}, []int{1, 2, 3}, false, true, false},
+		{[]int{1, 4}, []int{1, 2, 3}, false, false, false},
+		{[]int{1, 2}, []int{3, 4}, false, false, true},
+		{[]int{0, 2, 4}, []int{1, 3, 5}, false, false, true},
+	} {
+		a, b := intSet(c.a...), intSet(c.b...)
+		if a.Equal(b) != c.equal || b.Equal(a) != c.equal {
+			t.Errorf("%v.Equal(%v) != %v", c.a, c.b, c.equal)
+		}
+		if a.IsSubsetOf(b) != c.subset || b.IsSupersetOf(a) != c.subset {
+			t.Errorf("%v.IsSubsetOf(%v) != %v", c.a, c.b, c.subset)
+		}
+		if a.IsDisjointFrom(b) != c.disjoint || b.IsDisjointFrom(a) != c.disjoint {
+			t.Errorf("%v.IsDisjointFrom(%v) != %v", c.a, c.b, c.disjoint)
+		}
+	}
+}
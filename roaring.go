@@ -0,0 +1,429 @@
+package set
+
+import (
+	"cmp"
+	"math/bits"
+	"sort"
+)
+
+// RoaringSet is a hybrid set engine for uint32 keys in the style of
+// Roaring bitmaps. Keys are grouped into chunks by their high 16 bits and
+// each chunk stores its low 16 bits in whichever container is smallest: a
+// sorted array for sparse chunks, a bitmap for dense ones, or a list of
+// runs for clustered ones. The chunks themselves are kept in a Set.
+type RoaringSet struct {
+	chunks *Set[*roaringChunk]
+	size   int
+}
+
+type roaringChunk struct {
+	high uint16
+	c    container
+}
+
+// container holds the low 16 bits of the keys in one chunk. Mutations
+// return the container to use from then on, which may have changed kind.
+type container interface {
+	add(v uint16) (container, bool)
+	remove(v uint16) (container, bool)
+	contains(v uint16) bool
+	cardinality() int
+	each(fn func(v uint16) bool) bool
+}
+
+// arrayMaxSize is the cardinality above which a bitmap is smaller than a
+// sorted array
+const arrayMaxSize = 4096
+
+// NewRoaringSet creates an empty roaring set
+func NewRoaringSet() *RoaringSet {
+	return &RoaringSet{
+		chunks: NewSet(func(a, b *roaringChunk) int {
+			return cmp.Compare(a.high, b.high)
+		}),
+	}
+}
+
+// Size returns the number of elements in the set
+func (r *RoaringSet) Size() int {
+	return r.size
+}
+
+// IsEmpty returns true if the set has no elements
+func (r *RoaringSet) IsEmpty() bool {
+	return r.size == 0
+}
+
+// Clear removes all elements from the set
+func (r *RoaringSet) Clear() {
+	r.chunks.Clear()
+	r.size = 0
+}
+
+// Insert adds a new element to the set
+func (r *RoaringSet) Insert(key uint32) bool {
+	high, low := uint16(key>>16), uint16(key)
+	chunk := r.chunk(high)
+	if chunk == nil {
+		chunk = &roaringChunk{high: high, c: arrayContainer{}}
+		r.chunks.Insert(chunk)
+	}
+	c, ok := chunk.c.add(low)
+	chunk.c = c
+	if ok {
+		r.size++
+	}
+	return ok
+}
+
+// Remove removes an element from the set
+func (r *RoaringSet) Remove(key uint32) bool {
+	chunk := r.chunk(uint16(key >> 16))
+	if chunk == nil {
+		return false
+	}
+	c, ok := chunk.c.remove(uint16(key))
+	chunk.c = c
+	if !ok {
+		return false
+	}
+	r.size--
+	if c.cardinality() == 0 {
+		r.chunks.Remove(chunk)
+	}
+	return true
+}
+
+// Contains checks if an element exists in the set
+func (r *RoaringSet) Contains(key uint32) bool {
+	chunk := r.chunk(uint16(key >> 16))
+	return chunk != nil && chunk.c.contains(uint16(key))
+}
+
+// Ascend calls fn for every element in ascending order until fn returns
+// false
+func (r *RoaringSet) Ascend(fn func(key uint32) bool) {
+	for it := r.chunks.Begin(); it.Valid(); it.Next() {
+		chunk := it.Value()
+		high := uint32(chunk.high) << 16
+		if !chunk.c.each(func(v uint16) bool { return fn(high | uint32(v)) }) {
+			return
+		}
+	}
+}
+
+// RunOptimize converts every chunk to run-length encoding where that is
+// the smallest representation. Chunks converted to runs switch back to an
+// array or bitmap on their next mutation.
+func (r *RoaringSet) RunOptimize() {
+	for it := r.chunks.Begin(); it.Valid(); it.Next() {
+		chunk := it.Value()
+		runs := toRuns(chunk.c)
+		card := chunk.c.cardinality()
+		size := 2 * card
+		if card > arrayMaxSize {
+			size = 8192
+		}
+		if 4*len(runs) < size {
+			chunk.c = runs
+		}
+	}
+}
+
+// Union returns a new set holding the elements of both sets
+func (r *RoaringSet) Union(other *RoaringSet) *RoaringSet {
+	result := NewRoaringSet()
+	a, b := r.chunks.Begin(), other.chunks.Begin()
+	for a.Valid() || b.Valid() {
+		var ca, cb *roaringChunk
+		if a.Valid() {
+			ca = a.Value()
+		}
+		if b.Valid() {
+			cb = b.Value()
+		}
+		switch {
+		case cb == nil || ca != nil && ca.high < cb.high:
+			result.addChunk(ca.high, unionContainers(ca.c, nil))
+			a.Next()
+		case ca == nil || cb.high < ca.high:
+			result.addChunk(cb.high, unionContainers(cb.c, nil))
+			b.Next()
+		default:
+			result.addChunk(ca.high, unionContainers(ca.c, cb.c))
+			a.Next()
+			b.Next()
+		}
+	}
+	return result
+}
+
+// Intersection returns a new set holding the elements present in both sets
+func (r *RoaringSet) Intersection(other *RoaringSet) *RoaringSet {
+	result := NewRoaringSet()
+	for it := r.chunks.Begin(); it.Valid(); it.Next() {
+		ca := it.Value()
+		cb := other.chunk(ca.high)
+		if cb == nil {
+			continue
+		}
+		if c := intersectContainers(ca.c, cb.c); c.cardinality() > 0 {
+			result.addChunk(ca.high, c)
+		}
+	}
+	return result
+}
+
+func (r *RoaringSet) chunk(high uint16) *roaringChunk {
+	node := r.chunks.find(&roaringChunk{high: high})
+	if node == nil {
+		return nil
+	}
+	return node.key
+}
+
+func (r *RoaringSet) addChunk(high uint16, c container) {
+	r.chunks.Insert(&roaringChunk{high: high, c: c})
+	r.size += c.cardinality()
+}
+
+func unionContainers(a, b container) container {
+	ba, aIsBitmap := a.(*bitmapContainer)
+	bb, bIsBitmap := b.(*bitmapContainer)
+	if aIsBitmap && bIsBitmap {
+		out := &bitmapContainer{}
+		for i := range out.words {
+			out.words[i] = ba.words[i] | bb.words[i]
+			out.card += bits.OnesCount64(out.words[i])
+		}
+		return out
+	}
+	var out container = arrayContainer{}
+	for _, c := range []container{a, b} {
+		if c == nil {
+			continue
+		}
+		c.each(func(v uint16) bool {
+			out, _ = out.add(v)
+			return true
+		})
+	}
+	return out
+}
+
+func intersectContainers(a, b container) container {
+	ba, aIsBitmap := a.(*bitmapContainer)
+	bb, bIsBitmap := b.(*bitmapContainer)
+	if aIsBitmap && bIsBitmap {
+		out := &bitmapContainer{}
+		for i := range out.words {
+			out.words[i] = ba.words[i] & bb.words[i]
+			out.card += bits.OnesCount64(out.words[i])
+		}
+		if out.card <= arrayMaxSize {
+			return out.toArray()
+		}
+		return out
+	}
+	if a.cardinality() > b.cardinality() {
+		a, b = b, a
+	}
+	var out container = arrayContainer{}
+	a.each(func(v uint16) bool {
+		if b.contains(v) {
+			out, _ = out.add(v)
+		}
+		return true
+	})
+	return out
+}
+
+// arrayContainer stores a sparse chunk as sorted values
+type arrayContainer []uint16
+
+func (a arrayContainer) search(v uint16) int {
+	return sort.Search(len(a), func(i int) bool { return a[i] >= v })
+}
+
+func (a arrayContainer) add(v uint16) (container, bool) {
+	i := a.search(v)
+	if i < len(a) && a[i] == v {
+		return a, false
+	}
+	if len(a) >= arrayMaxSize {
+		b := a.toBitmap()
+		return b.add(v)
+	}
+	a = append(a, 0)
+	copy(a[i+1:], a[i:])
+	a[i] = v
+	return a, true
+}
+
+func (a arrayContainer) remove(v uint16) (container, bool) {
+	i := a.search(v)
+	if i == len(a) || a[i] != v {
+		return a, false
+	}
+	return append(a[:i], a[i+1:]...), true
+}
+
+func (a arrayContainer) contains(v uint16) bool {
+	i := a.search(v)
+	return i < len(a) && a[i] == v
+}
+
+func (a arrayContainer) cardinality() int {
+	return len(a)
+}
+
+func (a arrayContainer) each(fn func(v uint16) bool) bool {
+	for _, v := range a {
+		if !fn(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a arrayContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{card: len(a)}
+	for _, v := range a {
+		b.words[v>>6] |= 1 << (v & 63)
+	}
+	return b
+}
+
+// bitmapContainer stores a dense chunk as one bit per value
+type bitmapContainer struct {
+	words [1024]uint64
+	card  int
+}
+
+func (b *bitmapContainer) add(v uint16) (container, bool) {
+	mask := uint64(1) << (v & 63)
+	if b.words[v>>6]&mask != 0 {
+		return b, false
+	}
+	b.words[v>>6] |= mask
+	b.card++
+	return b, true
+}
+
+func (b *bitmapContainer) remove(v uint16) (container, bool) {
+	mask := uint64(1) << (v & 63)
+	if b.words[v>>6]&mask == 0 {
+		return b, false
+	}
+	b.words[v>>6] &^= mask
+	b.card--
+	if b.card <= arrayMaxSize {
+		return b.toArray(), true
+	}
+	return b, true
+}
+
+func (b *bitmapContainer) contains(v uint16) bool {
+	return b.words[v>>6]&(1<<(v&63)) != 0
+}
+
+func (b *bitmapContainer) cardinality() int {
+	return b.card
+}
+
+func (b *bitmapContainer) each(fn func(v uint16) bool) bool {
+	for i, word := range b.words {
+		for word != 0 {
+			if !fn(uint16(i<<6 + bits.TrailingZeros64(word))) {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+	return true
+}
+
+func (b *bitmapContainer) toArray() arrayContainer {
+	a := make(arrayContainer, 0, b.card)
+	b.each(func(v uint16) bool {
+		a = append(a, v)
+		return true
+	})
+	return a
+}
+
+// runContainer stores a clustered chunk as sorted, non-adjacent runs
+type runContainer []valueRun
+
+type valueRun struct {
+	start, last uint16
+}
+
+func toRuns(c container) runContainer {
+	var runs runContainer
+	c.each(func(v uint16) bool {
+		if n := len(runs); n > 0 && runs[n-1].last+1 == v {
+			runs[n-1].last = v
+		} else {
+			runs = append(runs, valueRun{start: v, last: v})
+		}
+		return true
+	})
+	return runs
+}
+
+// expand converts the runs back to an array or bitmap for mutation
+func (r runContainer) expand() container {
+	if r.cardinality() > arrayMaxSize {
+		b := &bitmapContainer{}
+		r.each(func(v uint16) bool {
+			b.add(v)
+			return true
+		})
+		return b
+	}
+	a := make(arrayContainer, 0, r.cardinality())
+	r.each(func(v uint16) bool {
+		a = append(a, v)
+		return true
+	})
+	return a
+}
+
+func (r runContainer) add(v uint16) (container, bool) {
+	if r.contains(v) {
+		return r, false
+	}
+	return r.expand().add(v)
+}
+
+func (r runContainer) remove(v uint16) (container, bool) {
+	if !r.contains(v) {
+		return r, false
+	}
+	return r.expand().remove(v)
+}
+
+func (r runContainer) contains(v uint16) bool {
+	i := sort.Search(len(r), func(i int) bool { return r[i].last >= v })
+	return i < len(r) && r[i].start <= v
+}
+
+func (r runContainer) cardinality() int {
+	n := 0
+	for _, run := range r {
+		n += int(run.last-run.start) + 1
+	}
+	return n
+}
+
+func (r runContainer) each(fn func(v uint16) bool) bool {
+	for _, run := range r {
+		for v := int(run.start); v <= int(run.last); v++ {
+			if !fn(uint16(v)) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package set
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkRoaring fails t unless r holds exactly the keys of want
+func checkRoaring(t *testing.T, r *RoaringSet, want map[uint32]bool) {
+	t.Helper()
+	keys := make([]uint32, 0, len(want))
+	for key := range want {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	var got []uint32
+	r.Ascend(func(key uint32) bool {
+		got = append(got, key)
+		return true
+	})
+	if !slices.Equal(got, keys) || r.Size() != len(keys) {
+		t.Fatalf("roaring set holds %d keys (size %d), want %d", len(got), r.Size(), len(keys))
+	}
+	for _, key := range keys {
+		if !r.Contains(key) {
+			t.Fatalf("Contains(%d) = false", key)
+		}
+	}
+}
+
+// containerKind names the container type of the chunk holding key
+func containerKind(r *RoaringSet, key uint32) string {
+	switch r.chunk(uint16(key >> 16)).c.(type) {
+	case arrayContainer:
+		return "array"
+	case *bitmapContainer:
+		return "bitmap"
+	case runContainer:
+		return "run"
+	}
+	return "unknown"
+}
+
+func TestRoaringContainers(t *testing.T) {
+	r := NewRoaringSet()
+	want := map[uint32]bool{}
+	for i := uint32(0); i < arrayMaxSize; i++ {
+		r.Insert(3 * i)
+		want[3*i] = true
+	}
+	if kind := containerKind(r, 0); kind != "array" {
+		t.Fatalf("chunk of %d keys is a %s, want array", arrayMaxSize, kind)
+	}
+	r.Insert(1)
+	want[1] = true
+	if kind := containerKind(r, 0); kind != "bitmap" {
+		t.Fatalf("chunk past the array limit is a %s, want bitmap", kind)
+	}
+	checkRoaring(t, r, want)
+	r.Remove(1)
+	delete(want, 1)
+	if kind := containerKind(r, 0); kind != "array" {
+		t.Fatalf("chunk back at the array limit is a %s, want array", kind)
+	}
+	checkRoaring(t, r, want)
+}
+
+func TestRoaringRuns(t *testing.T) {
+	r := NewRoaringSet()
+	want := map[uint32]bool{}
+	for i := uint32(1000); i < 9000; i++ {
+		r.Insert(i)
+		want[i] = true
+	}
+	r.RunOptimize()
+	if kind := containerKind(r, 1000); kind != "run" {
+		t.Fatalf("a single run of keys is a %s after RunOptimize", kind)
+	}
+	checkRoaring(t, r, want)
+	r.Remove(5000)
+	delete(want, 5000)
+	r.Insert(20000)
+	want[20000] = true
+	if kind := containerKind(r, 1000); kind == "run" {
+		t.Fatal("a run container was mutated in place")
+	}
+	checkRoaring(t, r, want)
+}
+
+func TestRoaringRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	r := NewRoaringSet()
+	want := map[uint32]bool{}
+	for i := 0; i < 50000; i++ {
+		// Three dense chunks and sparse keys elsewhere
+		key := uint32(rng.Intn(3<<16)) | uint32(rng.Intn(4))<<20
+		if rng.Intn(4) == 0 {
+			if r.Remove(key) != want[key] {
+				t.Fatalf("Remove(%d) disagrees with the reference", key)
+			}
+			delete(want, key)
+		} else {
+			if r.Insert(key) == want[key] {
+				t.Fatalf("Insert(%d) disagrees with the reference", key)
+			}
+			want[key] = true
+		}
+	}
+	checkRoaring(t, r, want)
+	r.RunOptimize()
+	checkRoaring(t, r, want)
+}
+
+func TestRoaringSetOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	a, b := NewRoaringSet(), NewRoaringSet()
+	inA, inB := map[uint32]bool{}, map[uint32]bool{}
+	for i := 0; i < 20000; i++ {
+		key := uint32(rng.Intn(1 << 18))
+		if i%2 == 0 {
+			a.Insert(key)
+			inA[key] = true
+		} else {
+			b.Insert(key)
+			inB[key] = true
+		}
+	}
+	// Values below 1<<16 are dense in both sets, so their chunks are
+	// bitmaps on both sides
+	for i := uint32(0); i < 6000; i++ {
+		a.Insert(i)
+		inA[i] = true
+		b.Insert(i + 100)
+		inB[i+100] = true
+	}
+	union, both := map[uint32]bool{}, map[uint32]bool{}
+	for key := range inA {
+		union[key] = true
+		if inB[key] {
+			both[key] = true
+		}
+	}
+	for key := range inB {
+		union[key] = true
+	}
+	u := a.Union(b)
+	checkRoaring(t, u, union)
+	checkRoaring(t, a.Intersection(b), both)
+	// The results must not share containers with their operands
+	u.Insert(1<<20 + 5)
+	u.Remove(50)
+	checkRoaring(t, a, inA)
+	checkRoaring(t, b, inB)
+}
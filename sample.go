@@ -0,0 +1,55 @@
+package set
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// RandomOne returns a uniformly random element in O(log n) time by picking
+// a random index and descending by subtree sizes. A nil rng uses the
+// default source of math/rand.
+func (s *Set[T]) RandomOne(rng *rand.Rand) (T, bool) {
+	if s.size == 0 {
+		return keyOf[T](nil)
+	}
+	return keyOf(s.at(randIntn(rng, s.size)))
+}
+
+// Sample returns k distinct elements chosen uniformly at random, in
+// ascending order, or all elements if the set holds fewer than k. Indices
+// are drawn with Floyd's algorithm and looked up by rank, so it takes
+// O(k log n) time regardless of the size of the set. A nil rng uses the
+// default source of math/rand.
+func (s *Set[T]) Sample(k int, rng *rand.Rand) []T {
+	if k >= s.size {
+		return s.keys()
+	}
+	if k <= 0 {
+		return nil
+	}
+	chosen := make(map[int]struct{}, k)
+	for j := s.size - k; j < s.size; j++ {
+		i := randIntn(rng, j+1)
+		if _, ok := chosen[i]; ok {
+			i = j
+		}
+		chosen[i] = struct{}{}
+	}
+	indices := make([]int, 0, k)
+	for i := range chosen {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	sample := make([]T, k)
+	for i, index := range indices {
+		sample[i] = s.at(index).key
+	}
+	return sample
+}
+
+func randIntn(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return rand.Intn(n)
+	}
+	return rng.Intn(n)
+}
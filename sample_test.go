@@ -0,0 +1,55 @@
+package set
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestRandomOne(t *testing.T) {
+	if _, ok := intSet().RandomOne(nil); ok {
+		t.Fatal("RandomOne of an empty set succeeded")
+	}
+	rng := rand.New(rand.NewSource(1))
+	s := intSet(0, 1, 2, 3)
+	counts := make([]int, 4)
+	for i := 0; i < 4000; i++ {
+		key, _ := s.RandomOne(rng)
+		counts[key]++
+	}
+	for key, n := range counts {
+		if n < 850 || n > 1150 {
+			t.Fatalf("%d was picked %d times out of 4000: %v", key, n, counts)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := intSet()
+	for i := 0; i < 10; i++ {
+		s.Insert(i)
+	}
+	counts := make([]int, 10)
+	for round := 0; round < 2000; round++ {
+		sample := s.Sample(3, rng)
+		if len(sample) != 3 || !slices.IsSorted(sample) || sample[0] == sample[1] || sample[1] == sample[2] {
+			t.Fatalf("Sample(3) = %v", sample)
+		}
+		for _, key := range sample {
+			counts[key]++
+		}
+	}
+	// Every element is drawn with probability 3/10
+	for key, n := range counts {
+		if n < 500 || n > 700 {
+			t.Fatalf("%d was sampled %d times out of 2000: %v", key, n, counts)
+		}
+	}
+	if got := s.Sample(20, rng); !slices.Equal(got, s.ToSlice()) {
+		t.Fatalf("oversized Sample = %v", got)
+	}
+	if got := s.Sample(0, nil); got != nil {
+		t.Fatalf("Sample(0) = %v", got)
+	}
+}
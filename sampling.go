@@ -0,0 +1,57 @@
+package set
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Operation identifies a set operation reported to a latency observer
+type Operation int
+
+const (
+	OpInsert Operation = iota
+	OpRemove
+	OpContains
+)
+
+// String returns the name of the operation
+func (op Operation) String() string {
+	switch op {
+	case OpInsert:
+		return "insert"
+	case OpRemove:
+		return "remove"
+	case OpContains:
+		return "contains"
+	}
+	return "unknown"
+}
+
+// WithLatencySampling times one in every n Insert, Remove and Contains calls
+// and reports the duration to observe, which typically records it in a
+// histogram of the caller's metrics system
+func WithLatencySampling[T any](n int, observe func(Operation, time.Duration)) Option[T] {
+	return func(s *Set[T]) {
+		if n < 1 {
+			n = 1
+		}
+		s.sampler = &sampler{every: uint64(n), observe: observe}
+	}
+}
+
+type sampler struct {
+	every   uint64
+	count   atomic.Uint64
+	observe func(Operation, time.Duration)
+}
+
+// sample reports whether the current call should be timed. The counter is
+// atomic so that concurrent readers sharing a set under a read lock can
+// sample safely.
+func (sp *sampler) sample() bool {
+	return sp.count.Add(1)%sp.every == 0
+}
+
+func (sp *sampler) done(op Operation, start time.Time) {
+	sp.observe(op, time.Since(start))
+}
@@ -0,0 +1,34 @@
+package set
+
+import (
+	"cmp"
+	"testing"
+	"time"
+)
+
+func TestWithLatencySampling(t *testing.T) {
+	counts := map[Operation]int{}
+	s := NewSetWithOptions(cmp.Compare[int], WithLatencySampling[int](3, func(op Operation, d time.Duration) {
+		if d < 0 {
+			t.Errorf("%v took %v", op, d)
+		}
+		counts[op]++
+	}))
+	for i := 0; i < 9; i++ {
+		s.Insert(i)
+	}
+	for i := 0; i < 9; i++ {
+		s.Contains(i)
+		s.Remove(i)
+	}
+	// One call in three is timed, counted across operations
+	if total := counts[OpInsert] + counts[OpContains] + counts[OpRemove]; total != 9 {
+		t.Fatalf("sampled %d of 27 calls, want 9: %v", total, counts)
+	}
+	if counts[OpInsert] != 3 {
+		t.Fatalf("sampled %d of 9 inserts, want 3", counts[OpInsert])
+	}
+	if OpRemove.String() != "remove" || Operation(99).String() != "unknown" {
+		t.Fatal("Operation.String names the wrong operation")
+	}
+}
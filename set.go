@@ -1,6 +1,11 @@
-// Package set provides a Red-Black Tree based Set implementation
+// Package set provides a generic Red-Black Tree based Set implementation
 package set
 
+import (
+	"cmp"
+	"time"
+)
+
 // Color represents the color of a node in the Red-Black tree
 type Color bool
 
@@ -10,211 +15,726 @@ const (
 )
 
 // Node represents a node in the Red-Black tree
-type Node struct {
-	key                     interface{}
-	color                   Color
-	left, right, parent     *Node
+type Node[T any] struct {
+	key                 T
+	color               Color
+	left, right, parent *Node[T]
+	size                int    // number of nodes in the subtree rooted here
+	gen                 uint64 // generation of the set that may write the node in place
 }
 
 // Set represents the Red-Black tree based set
-type Set struct {
-	root    *Node
-	size    int
-	compare func(interface{}, interface{}) int
+type Set[T any] struct {
+	root      *Node[T]
+	size      int
+	compare   func(T, T) int
+	clone     func(T) T
+	normalize func(T) T
+	loader    func(T) (T, bool)
+	alarms    []threshold[T]
+	sampler   *sampler
+	scopes    int
+	changes   *changeFeed[T]
+	onInsert  []func(T)
+	onRemove  []func(T)
+	nilKeys   NilKeyPolicy
+	maxSize   int            // limit on the number of elements, if positive
+	safeIters bool           // iterators re-seek after modifications instead of panicking
+	counters  *opCounters    // operation counts, if collected
+	gen       uint64         // nodes of another generation are shared and copied before writing
+	detached  bool           // parent pointers of shared nodes may be stale, as in snapshots
+	mods      uint64         // structural modification count checked by iterators
+	augment   func(*Node[T]) // recomputes per-subtree data kept in a node's key
+	rotations [2]uint64      // left and right rotations since creation
+	nodes     nodeAllocator[T]
+}
+
+type threshold[T any] struct {
+	size int
+	fn   func(*Set[T])
 }
 
 // Iterator represents a bidirectional iterator for the set
-type Iterator struct {
-	node    *Node
-	set     *Set
+type Iterator[T any] struct {
+	node    *Node[T]
+	set     *Set[T]
 	reverse bool
+	marks   []*Node[T]
+	mods    uint64
+	stop    *Node[T] // first element past the limit, if bounded
+	bounded bool
+	key     T               // current element, remembered by safe iterators to re-seek
+	relimit func() *Node[T] // locates stop again after a modification
 }
 
 // NewSet creates a new set with a custom comparator
-func NewSet(compare func(interface{}, interface{}) int) *Set {
-	return &Set{
+func NewSet[T any](compare func(T, T) int) *Set[T] {
+	requireComparator(compare)
+	return &Set[T]{
 		compare: compare,
 	}
 }
 
+// NewOrdered creates a new set of an ordered type using cmp.Compare
+func NewOrdered[T cmp.Ordered]() *Set[T] {
+	return NewSet(cmp.Compare[T])
+}
+
 // Size returns the number of elements in the set
-func (s *Set) Size() int {
+func (s *Set[T]) Size() int {
 	return s.size
 }
 
 // Clear removes all elements from the set
-func (s *Set) Clear() {
+func (s *Set[T]) Clear() {
+	s.checkMutable()
+	old, root := s.size, s.root
 	s.root = nil
 	s.size = 0
+	s.mods++
+	if s.nodes != nil {
+		s.nodes.reset()
+	}
+	s.resized(old)
+	s.cleared(root)
 }
 
 // IsEmpty returns true if the set has no elements
-func (s *Set) IsEmpty() bool {
+func (s *Set[T]) IsEmpty() bool {
 	return s.size == 0
 }
 
+// OnThreshold registers fn to be called whenever the size of the set grows
+// to at least size or shrinks back below it
+func (s *Set[T]) OnThreshold(size int, fn func(*Set[T])) {
+	s.alarms = append(s.alarms, threshold[T]{size: size, fn: fn})
+}
+
 // Insert adds a new element to the set
-func (s *Set) Insert(key interface{}) bool {
-	if s.root == nil {
-		s.root = &Node{
-			key:     key,
-			color:   Black,
-		}
-		s.size++
-		return true
+func (s *Set[T]) Insert(key T) bool {
+	s.checkMutable()
+	if s.sampler != nil && s.sampler.sample() {
+		defer s.sampler.done(OpInsert, time.Now())
+	}
+	_, inserted := s.insert(s.canonical(key))
+	if s.counters != nil {
+		s.counters.record(OpInsert, inserted)
 	}
+	return inserted
+}
 
-	node := s.root
-	var parent *Node
+// Contains checks if an element exists in the set
+func (s *Set[T]) Contains(key T) bool {
+	if s.sampler != nil && s.sampler.sample() {
+		defer s.sampler.done(OpContains, time.Now())
+	}
+	found := s.lookup(s.canonical(key)) != nil
+	if s.counters != nil {
+		s.counters.record(OpContains, found)
+	}
+	return found
+}
 
+// Remove removes an element from the set
+func (s *Set[T]) Remove(key T) bool {
+	s.checkMutable()
+	if s.sampler != nil && s.sampler.sample() {
+		defer s.sampler.done(OpRemove, time.Now())
+	}
+	key = s.canonical(key)
+	node := s.root
 	for node != nil {
-		parent = node
 		cmp := s.compare(key, node.key)
 		if cmp == 0 {
-			return false // Key already exists
+			s.removeNode(node)
+			if s.counters != nil {
+				s.counters.record(OpRemove, true)
+			}
+			return true
 		} else if cmp < 0 {
 			node = node.left
 		} else {
 			node = node.right
 		}
 	}
+	if s.counters != nil {
+		s.counters.record(OpRemove, false)
+	}
+	return false
+}
 
-	newNode := &Node{
-		key:     key,
-		color:   Red,
-		parent:  parent,
+// Find returns the element stored in the set that is equal to key. Under a
+// comparator that only looks at part of the element, this is how the rest
+// of the stored element is retrieved.
+func (s *Set[T]) Find(key T) (T, bool) {
+	if s.sampler != nil && s.sampler.sample() {
+		defer s.sampler.done(OpContains, time.Now())
 	}
+	node := s.lookup(s.canonical(key))
+	if s.counters != nil {
+		s.counters.record(OpContains, node != nil)
+	}
+	return keyOf(node)
+}
 
-	if s.compare(key, parent.key) < 0 {
-		parent.left = newNode
-	} else {
-		parent.right = newNode
+// InsertOrGet adds key unless an equal element is present and returns the
+// stored element in either case, with true if key was inserted. Both cases
+// take a single descent, which makes it suitable for interning. A full
+// set created WithMaxSize returns key itself and false for a new key.
+func (s *Set[T]) InsertOrGet(key T) (T, bool) {
+	s.checkMutable()
+	if s.sampler != nil && s.sampler.sample() {
+		defer s.sampler.done(OpInsert, time.Now())
+	}
+	node, inserted := s.insert(s.canonical(key))
+	if node == nil {
+		return key, false
 	}
+	return node.key, inserted
+}
 
-	s.size++
-	s.insertFixup(newNode)
-	return true
+// Replace stores key, overwriting an equal element if there is one, and
+// returns the element it replaced. Change subscribers see a replacement as
+// a removal of the old element followed by an insertion of the new one.
+// A full set created WithMaxSize only replaces existing elements.
+func (s *Set[T]) Replace(key T) (T, bool) {
+	s.checkMutable()
+	if s.sampler != nil && s.sampler.sample() {
+		defer s.sampler.done(OpInsert, time.Now())
+	}
+	key = s.canonical(key)
+	node, inserted := s.insert(key)
+	if inserted || node == nil {
+		return keyOf[T](nil)
+	}
+	if node.gen != s.gen {
+		node, _ = s.ownPath(node.key)
+	}
+	old := node.key
+	node.key = s.stored(key)
+	// The new element may carry different augmented data, such as the
+	// upper bound of an interval
+	if s.augment != nil {
+		for n := node; n != nil; n = n.parent {
+			s.augment(n)
+		}
+	}
+	s.changed(ChangeRemove, old)
+	s.changed(ChangeInsert, node.key)
+	return old, true
 }
 
-// Contains checks if an element exists in the set
-func (s *Set) Contains(key interface{}) bool {
-	node := s.root
-	for node != nil {
-		cmp := s.compare(key, node.key)
-		if cmp == 0 {
-			return true
-		} else if cmp < 0 {
-			node = node.left
-		} else {
-			node = node.right
+// Min returns the smallest element
+func (s *Set[T]) Min() (T, bool) {
+	if s.root == nil {
+		return keyOf[T](nil)
+	}
+	return s.minimum(s.root).key, true
+}
+
+// Max returns the largest element
+func (s *Set[T]) Max() (T, bool) {
+	if s.root == nil {
+		return keyOf[T](nil)
+	}
+	return s.maximum(s.root).key, true
+}
+
+// PopMin removes and returns the smallest element
+func (s *Set[T]) PopMin() (T, bool) {
+	s.checkMutable()
+	if s.root == nil {
+		return keyOf[T](nil)
+	}
+	node := s.minimum(s.root)
+	key := node.key
+	s.removeNode(node)
+	return key, true
+}
+
+// PopMax removes and returns the largest element
+func (s *Set[T]) PopMax() (T, bool) {
+	s.checkMutable()
+	if s.root == nil {
+		return keyOf[T](nil)
+	}
+	node := s.maximum(s.root)
+	key := node.key
+	s.removeNode(node)
+	return key, true
+}
+
+// PopUntil removes and returns, in ascending order, all elements less than
+// or equal to threshold
+func (s *Set[T]) PopUntil(threshold T) []T {
+	var popped []T
+	s.PopUntilFunc(threshold, func(key T) {
+		popped = append(popped, key)
+	})
+	return popped
+}
+
+// PopUntilFunc removes all elements less than or equal to threshold,
+// calling fn with each one in ascending order as it is removed
+func (s *Set[T]) PopUntilFunc(threshold T, fn func(key T)) {
+	s.checkMutable()
+	threshold = s.canonical(threshold)
+	for s.root != nil {
+		node := s.minimum(s.root)
+		if s.compare(node.key, threshold) > 0 {
+			return
 		}
+		key := node.key
+		s.removeNode(node)
+		fn(key)
 	}
-	return false
 }
 
-// Remove removes an element from the set
-func (s *Set) Remove(key interface{}) bool {
-	node := s.root
-	for node != nil {
-		cmp := s.compare(key, node.key)
-		if cmp == 0 {
-			s.delete(node)
-			s.size--
-			return true
-		} else if cmp < 0 {
-			node = node.left
-		} else {
-			node = node.right
+// ReplaceRange replaces all elements in [from, to) with the elements of
+// replacement that fall in the same interval. Replacement elements outside
+// [from, to) are ignored, so the rest of the set is never touched. The
+// range is walked once to remove it, as in RemoveRange.
+func (s *Set[T]) ReplaceRange(from, to T, replacement []T) {
+	s.checkMutable()
+	from, to = s.canonical(from), s.canonical(to)
+	node := s.lowerBound(from)
+	for node != nil && s.compare(node.key, to) < 0 {
+		next := s.successor(node)
+		s.removeNode(node)
+		node = s.live(next)
+	}
+	for _, key := range replacement {
+		key = s.canonical(key)
+		if s.compare(key, from) >= 0 && s.compare(key, to) < 0 {
+			s.insert(key)
 		}
 	}
-	return false
 }
 
-// Begin returns an iterator to the smallest element
-func (s *Set) Begin() *Iterator {
+// RemoveRange removes all elements in [from, to) and returns how many were
+// removed. The range is walked once from its first element rather than
+// searched for again after every removal.
+func (s *Set[T]) RemoveRange(from, to T) int {
+	s.checkMutable()
+	to = s.canonical(to)
+	removed := 0
+	node := s.lowerBound(s.canonical(from))
+	for node != nil && s.compare(node.key, to) < 0 {
+		next := s.successor(node)
+		s.removeNode(node)
+		removed++
+		node = s.live(next)
+	}
+	return removed
+}
+
+// RemoveIf removes all elements for which pred returns true in a single
+// in-order pass and returns how many were removed. pred must not modify
+// the set.
+func (s *Set[T]) RemoveIf(pred func(key T) bool) int {
+	s.checkMutable()
 	if s.root == nil {
-		return &Iterator{set: s}
+		return 0
 	}
-	return &Iterator{
-		node: s.minimum(s.root),
-		set:  s,
+	removed := 0
+	for node := s.minimum(s.root); node != nil; {
+		next := s.successor(node)
+		if pred(node.key) {
+			s.removeNode(node)
+			removed++
+			next = s.live(next)
+		}
+		node = next
 	}
+	return removed
+}
+
+// Begin returns an iterator to the smallest element
+func (s *Set[T]) Begin() *Iterator[T] {
+	return s.iterator(s.first(), false)
 }
 
 // End returns an iterator past the largest element
-func (s *Set) End() *Iterator {
-	return &Iterator{set: s}
+func (s *Set[T]) End() *Iterator[T] {
+	return s.iterator(nil, false)
 }
 
 // RBegin returns a reverse iterator to the largest element
-func (s *Set) RBegin() *Iterator {
+func (s *Set[T]) RBegin() *Iterator[T] {
 	if s.root == nil {
-		return &Iterator{set: s, reverse: true}
-	}
-	return &Iterator{
-		node:    s.maximum(s.root),
-		set:     s,
-		reverse: true,
+		return s.iterator(nil, true)
 	}
+	return s.iterator(s.maximum(s.root), true)
 }
 
 // REnd returns a reverse iterator before the smallest element
-func (s *Set) REnd() *Iterator {
-	return &Iterator{set: s, reverse: true}
+func (s *Set[T]) REnd() *Iterator[T] {
+	return s.iterator(nil, true)
+}
+
+// LowerBound returns an iterator to the first element not less than key
+func (s *Set[T]) LowerBound(key T) *Iterator[T] {
+	return s.iterator(s.lowerBound(s.canonical(key)), false)
+}
+
+// UpperBound returns an iterator to the first element greater than key
+func (s *Set[T]) UpperBound(key T) *Iterator[T] {
+	return s.iterator(s.upperBound(s.canonical(key)), false)
+}
+
+// EqualRange returns an iterator to the first element equal to key and an
+// iterator to the first element greater than key. Under the comparator of
+// the set they bracket at most one element; EqualRangeFunc matches a
+// coarser order. The first iterator is limited to the range, as with
+// SetLimit, so a forward scan from it ends by itself, and it is invalid
+// if no element matches.
+func (s *Set[T]) EqualRange(key T) (*Iterator[T], *Iterator[T]) {
+	key = s.canonical(key)
+	return s.bracket(s.lowerBound(key), func() *Node[T] { return s.upperBound(key) })
+}
+
+// EqualRangeFunc returns iterators bracketing the elements for which probe
+// returns 0. probe compares an element against the wanted range, returning
+// a negative number below it and a positive one above, and must agree with
+// the order of the set the way comparing by prefix agrees with comparing
+// whole strings. As with EqualRange, the first iterator is limited to the
+// range.
+func (s *Set[T]) EqualRangeFunc(probe func(key T) int) (*Iterator[T], *Iterator[T]) {
+	return s.bracket(s.search(probe, false), func() *Node[T] { return s.search(probe, true) })
+}
+
+// bracket returns iterators to first and to the end located by relimit,
+// the first one limited to stop at the end
+func (s *Set[T]) bracket(first *Node[T], relimit func() *Node[T]) (*Iterator[T], *Iterator[T]) {
+	end := relimit()
+	it := s.iterator(first, false)
+	it.stop, it.bounded, it.relimit = end, true, relimit
+	if first == end {
+		it.node = nil
+	}
+	return it, s.iterator(end, false)
+}
+
+// Floor returns the greatest element less than or equal to key
+func (s *Set[T]) Floor(key T) (T, bool) {
+	return keyOf(s.floor(s.canonical(key), true))
+}
+
+// Ceiling returns the smallest element greater than or equal to key
+func (s *Set[T]) Ceiling(key T) (T, bool) {
+	return keyOf(s.lowerBound(s.canonical(key)))
+}
+
+// Higher returns the smallest element strictly greater than key
+func (s *Set[T]) Higher(key T) (T, bool) {
+	return keyOf(s.upperBound(s.canonical(key)))
+}
+
+// Lower returns the greatest element strictly less than key
+func (s *Set[T]) Lower(key T) (T, bool) {
+	return keyOf(s.floor(s.canonical(key), false))
+}
+
+// At returns the element at index i in ascending order, or false if i is
+// out of range
+func (s *Set[T]) At(i int) (T, bool) {
+	return keyOf(s.at(i))
+}
+
+// Rank returns the number of elements less than key
+func (s *Set[T]) Rank(key T) int {
+	return s.rank(s.canonical(key))
+}
+
+// CountRange returns the number of elements in [from, to) in O(log n) time
+func (s *Set[T]) CountRange(from, to T) int {
+	return max(s.rank(s.canonical(to))-s.rank(s.canonical(from)), 0)
 }
 
 // Iterator methods
-func (it *Iterator) Value() interface{} {
+func (it *Iterator[T]) Value() T {
+	if it.node != nil && it.checkModified() {
+		it.relocate(true)
+	}
 	if it.node == nil {
-		return nil
+		var zero T
+		return zero
 	}
 	return it.node.key
 }
 
-func (it *Iterator) Valid() bool {
+func (it *Iterator[T]) Valid() bool {
+	if it.node != nil && it.set.safeIters && it.checkModified() {
+		it.relocate(true)
+	}
 	return it.node != nil
 }
 
-func (it *Iterator) Next() bool {
+func (it *Iterator[T]) Next() bool {
 	if it.node == nil {
 		return false
 	}
-	
+	if it.checkModified() {
+		it.relocate(true)
+		return it.node != nil
+	}
+
 	if it.reverse {
 		it.node = it.set.predecessor(it.node)
 	} else {
 		it.node = it.set.successor(it.node)
 	}
-	
+	if it.bounded && it.node == it.stop {
+		it.node = nil
+	}
+	it.remember()
+
 	return it.node != nil
 }
 
-func (it *Iterator) Prev() bool {
+func (it *Iterator[T]) Prev() bool {
+	if it.checkModified() {
+		it.relocate(false)
+		return it.node != nil
+	}
 	if it.node == nil {
-		if it.reverse {
+		if it.bounded && it.stop != nil {
+			if it.reverse {
+				it.node = it.set.successor(it.stop)
+			} else {
+				it.node = it.set.predecessor(it.stop)
+			}
+		} else if it.reverse {
 			it.node = it.set.minimum(it.set.root)
 		} else {
 			it.node = it.set.maximum(it.set.root)
 		}
+		it.remember()
 		return it.node != nil
 	}
-	
+
 	if it.reverse {
 		it.node = it.set.successor(it.node)
 	} else {
 		it.node = it.set.predecessor(it.node)
 	}
-	
+	it.remember()
+
 	return it.node != nil
 }
 
+// Push saves the current position on the iterator's bookmark stack
+func (it *Iterator[T]) Push() {
+	it.marks = append(it.marks, it.node)
+}
+
+// Pop restores the most recently pushed position, returning false if the
+// bookmark stack is empty
+func (it *Iterator[T]) Pop() bool {
+	if len(it.marks) == 0 {
+		return false
+	}
+	it.node = it.marks[len(it.marks)-1]
+	it.marks[len(it.marks)-1] = nil
+	it.marks = it.marks[:len(it.marks)-1]
+	it.remember()
+	return true
+}
+
+// Remove deletes the current element and moves to the next one in the
+// iterator's direction. It returns false if the iterator was not
+// positioned on an element. Bookmarks on the removed element move along
+// with the iterator; other iterators on it become invalid.
+func (it *Iterator[T]) Remove() bool {
+	if it.node == nil {
+		return false
+	}
+	if it.checkModified() {
+		it.relocate(true)
+		return false
+	}
+	it.set.checkMutable()
+	node := it.node
+	if it.reverse {
+		it.node = it.set.predecessor(node)
+	} else {
+		it.node = it.set.successor(node)
+	}
+	if it.bounded && it.node == it.stop {
+		it.node = nil
+	}
+	for i, mark := range it.marks {
+		if mark == node {
+			it.marks[i] = it.node
+		}
+	}
+	it.set.removeNode(node)
+	// The removal may have replaced shared nodes by copies
+	it.node, it.stop = it.set.live(it.node), it.set.live(it.stop)
+	for i, mark := range it.marks {
+		it.marks[i] = it.set.live(mark)
+	}
+	it.mods = it.set.mods
+	it.remember()
+	return true
+}
+
+// Erase deletes the element at it and returns an iterator to the element
+// that followed it. it itself must not be used afterwards.
+func (s *Set[T]) Erase(it *Iterator[T]) *Iterator[T] {
+	it.Remove()
+	next := s.iterator(it.node, it.reverse)
+	next.stop, next.bounded, next.relimit = it.stop, it.bounded, it.relimit
+	it.node = nil
+	return next
+}
+
+// SetLimit bounds the iterator at key, exclusively: a forward iterator
+// becomes invalid on reaching an element not less than key, a reverse one
+// on reaching an element not greater than it. The first element past the
+// limit is located once, so stepping costs no comparisons. Marks restored
+// with Pop are not checked against the limit.
+func (it *Iterator[T]) SetLimit(key T) {
+	s := it.set
+	key = s.canonical(key)
+	it.bounded = true
+	if it.reverse {
+		it.relimit = func() *Node[T] { return s.floor(key, true) }
+	} else {
+		it.relimit = func() *Node[T] { return s.lowerBound(key) }
+	}
+	it.stop = it.relimit()
+	if it.node == nil {
+		return
+	}
+	cmp := s.compare(it.node.key, key)
+	if it.reverse && cmp <= 0 || !it.reverse && cmp >= 0 {
+		it.node = nil
+	}
+}
+
+// BoundedIterator returns an iterator over the elements in [from, to),
+// which becomes invalid once it moves past the last of them
+func (s *Set[T]) BoundedIterator(from, to T) *Iterator[T] {
+	it := s.LowerBound(from)
+	it.SetLimit(to)
+	return it
+}
+
+// checkModified panics with ErrConcurrentModification if the set was
+// structurally modified other than through the iterator since it was
+// created. Safe iterators are brought up to date instead, and it reports
+// whether their element has been removed, leaving it to the caller which
+// neighbour to move to.
+func (it *Iterator[T]) checkModified() bool {
+	if it.mods == it.set.mods {
+		return false
+	}
+	if !it.set.safeIters {
+		panic(ErrConcurrentModification)
+	}
+	return it.resync()
+}
+
+// remember records the current element of a safe iterator, which its node
+// may no longer hold once removed
+func (it *Iterator[T]) remember() {
+	if it.set.safeIters && it.node != nil {
+		it.key = it.node.key
+	}
+}
+
+// resync brings a safe iterator up to date with the set and reports
+// whether its element is no longer in the tree. An element whose node was
+// replaced, by a copy-on-write or by removal and reinsertion, is followed
+// to the new node.
+func (it *Iterator[T]) resync() bool {
+	s := it.set
+	it.mods = s.mods
+	if it.bounded {
+		it.stop = it.relimit()
+	}
+	if it.node == nil || s.holds(it.node, it.key) {
+		return false
+	}
+	if node := s.find(it.key); node != nil {
+		it.node = node
+		return false
+	}
+	return true
+}
+
+// relocate moves a safe iterator whose element was removed to the element
+// that followed it in the iterator's direction, or to the one that
+// preceded it
+func (it *Iterator[T]) relocate(following bool) {
+	s := it.set
+	switch {
+	case following && !it.reverse:
+		it.node = s.lowerBound(it.key)
+	case following:
+		it.node = s.floor(it.key, true)
+	case !it.reverse:
+		it.node = s.floor(it.key, false)
+	default:
+		it.node = s.upperBound(it.key)
+	}
+	if following && it.bounded && it.node == it.stop {
+		it.node = nil
+	}
+	it.remember()
+}
+
 // Internal helper functions
-func (s *Set) leftRotate(x *Node) {
-	y := x.right
-	x.right = y.left
-	if y.left != nil {
-		y.left.parent = x
+func (s *Set[T]) iterator(node *Node[T], reverse bool) *Iterator[T] {
+	it := &Iterator[T]{node: node, set: s, reverse: reverse, mods: s.mods}
+	it.remember()
+	return it
+}
+
+// holds reports whether node is in the tree and still holds key, which a
+// removed node that was recycled for another element does not
+func (s *Set[T]) holds(node *Node[T], key T) bool {
+	if s.compare(node.key, key) != 0 {
+		return false
+	}
+	if s.detached && node.gen != s.gen {
+		return s.find(key) == node
+	}
+	// A node replaced by a copy still points to its old parent, which no
+	// longer points back to it
+	for node != s.root {
+		parent := node.parent
+		if parent == nil || parent.left != node && parent.right != node {
+			return false
+		}
+		node = parent
+	}
+	return true
+}
+
+func (s *Set[T]) canonical(key T) T {
+	if s.nilKeys == NilKeysRejected && isNil(key) {
+		panic(ErrNilKey)
+	}
+	if s.normalize != nil {
+		return s.normalize(key)
+	}
+	return key
+}
+
+func (s *Set[T]) stored(key T) T {
+	if s.clone != nil {
+		return s.clone(key)
 	}
+	return key
+}
+
+func (s *Set[T]) leftRotate(x *Node[T]) {
+	s.rotations[0]++
+	y := s.own(x, x.right)
+	x.right = y.left
+	s.setParent(y.left, x)
 	y.parent = x.parent
 	if x.parent == nil {
 		s.root = y
@@ -225,14 +745,19 @@ func (s *Set) leftRotate(x *Node) {
 	}
 	y.left = x
 	x.parent = y
+	y.size = x.size
+	x.size = 1 + sizeOf(x.left) + sizeOf(x.right)
+	if s.augment != nil {
+		s.augment(x)
+		s.augment(y)
+	}
 }
 
-func (s *Set) rightRotate(x *Node) {
-	y := x.left
+func (s *Set[T]) rightRotate(x *Node[T]) {
+	s.rotations[1]++
+	y := s.own(x, x.left)
 	x.left = y.right
-	if y.right != nil {
-		y.right.parent = x
-	}
+	s.setParent(y.right, x)
 	y.parent = x.parent
 	if x.parent == nil {
 		s.root = y
@@ -243,13 +768,20 @@ func (s *Set) rightRotate(x *Node) {
 	}
 	y.right = x
 	x.parent = y
+	y.size = x.size
+	x.size = 1 + sizeOf(x.left) + sizeOf(x.right)
+	if s.augment != nil {
+		s.augment(x)
+		s.augment(y)
+	}
 }
 
-func (s *Set) insertFixup(z *Node) {
+func (s *Set[T]) insertFixup(z *Node[T]) {
 	for z.parent != nil && z.parent.color == Red {
 		if z.parent == z.parent.parent.left {
 			y := z.parent.parent.right
 			if y != nil && y.color == Red {
+				y = s.own(z.parent.parent, y)
 				z.parent.color = Black
 				y.color = Black
 				z.parent.parent.color = Red
@@ -266,6 +798,7 @@ func (s *Set) insertFixup(z *Node) {
 		} else {
 			y := z.parent.parent.left
 			if y != nil && y.color == Red {
+				y = s.own(z.parent.parent, y)
 				z.parent.color = Black
 				y.color = Black
 				z.parent.parent.color = Red
@@ -284,24 +817,284 @@ func (s *Set) insertFixup(z *Node) {
 	s.root.color = Black
 }
 
-func (s *Set) minimum(x *Node) *Node {
+// insert adds key unless an equal key is present, returning the node that
+// holds it in either case. A full set returns no node for a new key.
+func (s *Set[T]) insert(key T) (*Node[T], bool) {
+	if s.root == nil {
+		s.root = s.newNode(s.stored(key), Black, nil)
+		s.size++
+		s.mods++
+		s.resized(s.size - 1)
+		s.changed(ChangeInsert, s.root.key)
+		return s.root, true
+	}
+
+	node := s.root
+	var parent *Node[T]
+
+	for node != nil {
+		parent = node
+		cmp := s.compare(key, node.key)
+		if cmp == 0 {
+			return node, false // Key already exists
+		} else if cmp < 0 {
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	if s.maxSize > 0 && s.size >= s.maxSize {
+		return nil, false
+	}
+	if parent.gen != s.gen {
+		_, parent = s.ownPath(key)
+	}
+
+	newNode := s.newNode(s.stored(key), Red, parent)
+
+	if s.compare(key, parent.key) < 0 {
+		parent.left = newNode
+	} else {
+		parent.right = newNode
+	}
+	for n := parent; n != nil; n = n.parent {
+		n.size++
+		if s.augment != nil {
+			s.augment(n)
+		}
+	}
+
+	s.size++
+	s.mods++
+	s.insertFixup(newNode)
+	s.resized(s.size - 1)
+	s.changed(ChangeInsert, newNode.key)
+	return newNode, true
+}
+
+// removeNode deletes node from the tree and updates the size. A shared
+// node is copied along with its path first, so callers holding other
+// nodes should pass them through live afterwards.
+func (s *Set[T]) removeNode(node *Node[T]) {
+	if node.gen != s.gen {
+		node, _ = s.ownPath(node.key)
+	}
+	key := node.key
+	s.delete(node)
+	s.size--
+	s.mods++
+	s.resized(s.size + 1)
+	s.changed(ChangeRemove, key)
+	if s.nodes != nil {
+		s.nodes.free(node)
+	}
+}
+
+// newNode returns a leaf node holding key, taken from the node allocator
+// if the set has one
+func (s *Set[T]) newNode(key T, color Color, parent *Node[T]) *Node[T] {
+	var node *Node[T]
+	if s.nodes != nil {
+		node = s.nodes.alloc()
+	} else {
+		node = &Node[T]{}
+	}
+	node.key = key
+	node.color = color
+	node.parent = parent
+	node.size = 1
+	node.gen = s.gen
+	return node
+}
+
+// emptyCopy returns an empty set sharing the comparator, key handling and
+// augment hook of s
+func (s *Set[T]) emptyCopy() *Set[T] {
+	return &Set[T]{
+		compare:   s.compare,
+		clone:     s.clone,
+		normalize: s.normalize,
+		nilKeys:   s.nilKeys,
+		augment:   s.augment,
+	}
+}
+
+// resized fires the threshold callbacks crossed by a size change from old
+func (s *Set[T]) resized(old int) {
+	for _, t := range s.alarms {
+		if (old < t.size) != (s.size < t.size) {
+			t.fn(s)
+		}
+	}
+}
+
+func (s *Set[T]) find(key T) *Node[T] {
+	node := s.root
+	for node != nil {
+		cmp := s.compare(key, node.key)
+		if cmp == 0 {
+			return node
+		} else if cmp < 0 {
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	return nil
+}
+
+// lowerBound returns the first node whose key is not less than key
+func (s *Set[T]) lowerBound(key T) *Node[T] {
+	var result *Node[T]
+	node := s.root
+	for node != nil {
+		if s.compare(node.key, key) >= 0 {
+			result = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	return result
+}
+
+// upperBound returns the first node whose key is greater than key
+func (s *Set[T]) upperBound(key T) *Node[T] {
+	var result *Node[T]
+	node := s.root
+	for node != nil {
+		if s.compare(node.key, key) > 0 {
+			result = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	return result
+}
+
+// search returns the first node for which probe is not negative, or
+// positive when strict is set
+func (s *Set[T]) search(probe func(T) int, strict bool) *Node[T] {
+	var result *Node[T]
+	node := s.root
+	for node != nil {
+		if cmp := probe(node.key); cmp > 0 || !strict && cmp == 0 {
+			result = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	return result
+}
+
+// floor returns the last node whose key is less than key, or equal to it
+// when inclusive is set
+func (s *Set[T]) floor(key T, inclusive bool) *Node[T] {
+	var result *Node[T]
+	node := s.root
+	for node != nil {
+		cmp := s.compare(node.key, key)
+		if cmp < 0 || inclusive && cmp == 0 {
+			result = node
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+	return result
+}
+
+// at returns the node at index i in ascending order
+func (s *Set[T]) at(i int) *Node[T] {
+	if i < 0 || i >= s.size {
+		return nil
+	}
+	node := s.root
+	for node != nil {
+		left := sizeOf(node.left)
+		switch {
+		case i < left:
+			node = node.left
+		case i > left:
+			i -= left + 1
+			node = node.right
+		default:
+			return node
+		}
+	}
+	return nil
+}
+
+// rank returns the number of nodes whose key is less than key
+func (s *Set[T]) rank(key T) int {
+	rank := 0
+	node := s.root
+	for node != nil {
+		if s.compare(key, node.key) <= 0 {
+			node = node.left
+		} else {
+			rank += sizeOf(node.left) + 1
+			node = node.right
+		}
+	}
+	return rank
+}
+
+// sizeOf returns the subtree size of node, which may be nil
+func sizeOf[T any](node *Node[T]) int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
+
+// keyOf returns the key of node, reporting false for a nil node
+func keyOf[T any](node *Node[T]) (T, bool) {
+	if node == nil {
+		var zero T
+		return zero, false
+	}
+	return node.key, true
+}
+
+// lookup finds key, falling through to the loader on a miss unless an
+// iteration scope is open. A loaded element that is not equal to key
+// counts as a miss and is not inserted.
+func (s *Set[T]) lookup(key T) *Node[T] {
+	if node := s.find(key); node != nil || s.loader == nil || s.scopes > 0 {
+		return node
+	}
+	loaded, ok := s.loader(key)
+	if !ok || s.compare(s.canonical(loaded), key) != 0 {
+		return nil
+	}
+	s.Insert(loaded)
+	return s.find(key)
+}
+
+func (s *Set[T]) minimum(x *Node[T]) *Node[T] {
 	for x.left != nil {
 		x = x.left
 	}
 	return x
 }
 
-func (s *Set) maximum(x *Node) *Node {
+func (s *Set[T]) maximum(x *Node[T]) *Node[T] {
 	for x.right != nil {
 		x = x.right
 	}
 	return x
 }
 
-func (s *Set) successor(x *Node) *Node {
+func (s *Set[T]) successor(x *Node[T]) *Node[T] {
 	if x.right != nil {
 		return s.minimum(x.right)
 	}
+	if s.detached && x.gen != s.gen {
+		return s.upperBound(x.key)
+	}
 	y := x.parent
 	for y != nil && x == y.right {
 		x = y
@@ -310,10 +1103,13 @@ func (s *Set) successor(x *Node) *Node {
 	return y
 }
 
-func (s *Set) predecessor(x *Node) *Node {
+func (s *Set[T]) predecessor(x *Node[T]) *Node[T] {
 	if x.left != nil {
 		return s.maximum(x.left)
 	}
+	if s.detached && x.gen != s.gen {
+		return s.floor(x.key, false)
+	}
 	y := x.parent
 	for y != nil && x == y.left {
 		x = y
@@ -322,50 +1118,72 @@ func (s *Set) predecessor(x *Node) *Node {
 	return y
 }
 
-func (s *Set) delete(z *Node) {
-	var x, y *Node
-	if z.left == nil || z.right == nil {
-		y = z
-	} else {
-		y = s.successor(z)
-	}
-
-	if y.left != nil {
-		x = y.left
-	} else {
-		x = y.right
-	}
-
-	if x != nil {
-		x.parent = y.parent
+// delete unlinks z from the tree. Other nodes keep their keys, so nodes
+// held by callers stay valid across the deletion.
+func (s *Set[T]) delete(z *Node[T]) {
+	var x, parent *Node[T]
+	color := z.color
+	switch {
+	case z.left == nil:
+		x, parent = z.right, z.parent
+		s.transplant(z, z.right)
+	case z.right == nil:
+		x, parent = z.left, z.parent
+		s.transplant(z, z.left)
+	default:
+		y := s.own(z, z.right)
+		for y.left != nil {
+			y = s.own(y, y.left)
+		}
+		color = y.color
+		x, parent = y.right, y
+		if y.parent != z {
+			parent = y.parent
+			s.transplant(y, y.right)
+			y.right = z.right
+			s.setParent(y.right, y)
+		}
+		s.transplant(z, y)
+		y.left = z.left
+		s.setParent(y.left, y)
+		y.color = z.color
+		y.size = z.size
 	}
-
-	if y.parent == nil {
-		s.root = x
-	} else if y == y.parent.left {
-		y.parent.left = x
-	} else {
-		y.parent.right = x
+	for n := parent; n != nil; n = n.parent {
+		n.size--
+		if s.augment != nil {
+			s.augment(n)
+		}
 	}
+	z.left, z.right, z.parent = nil, nil, nil
 
-	if y != z {
-		z.key = y.key
+	if color == Black {
+		s.deleteFixup(x, parent)
 	}
+}
 
-	if y.color == Black {
-		s.deleteFixup(x, y.parent)
+// transplant puts v, which may be nil, in the place of u
+func (s *Set[T]) transplant(u, v *Node[T]) {
+	switch {
+	case u.parent == nil:
+		s.root = v
+	case u == u.parent.left:
+		u.parent.left = v
+	default:
+		u.parent.right = v
 	}
+	s.setParent(v, u.parent)
 }
 
-func (s *Set) deleteFixup(x *Node, parent *Node) {
+func (s *Set[T]) deleteFixup(x *Node[T], parent *Node[T]) {
 	for x != s.root && (x == nil || x.color == Black) {
 		if x == parent.left {
-			w := parent.right
+			w := s.own(parent, parent.right)
 			if w.color == Red {
 				w.color = Black
 				parent.color = Red
 				s.leftRotate(parent)
-				w = parent.right
+				w = s.own(parent, parent.right)
 			}
 			if (w.left == nil || w.left.color == Black) &&
 				(w.right == nil || w.right.color == Black) {
@@ -375,27 +1193,27 @@ func (s *Set) deleteFixup(x *Node, parent *Node) {
 			} else {
 				if w.right == nil || w.right.color == Black {
 					if w.left != nil {
-						w.left.color = Black
+						s.own(w, w.left).color = Black
 					}
 					w.color = Red
 					s.rightRotate(w)
-					w = parent.right
+					w = s.own(parent, parent.right)
 				}
 				w.color = parent.color
 				parent.color = Black
 				if w.right != nil {
-					w.right.color = Black
+					s.own(w, w.right).color = Black
 				}
 				s.leftRotate(parent)
 				x = s.root
 			}
 		} else {
-			w := parent.left
+			w := s.own(parent, parent.left)
 			if w.color == Red {
 				w.color = Black
 				parent.color = Red
 				s.rightRotate(parent)
-				w = parent.left
+				w = s.own(parent, parent.left)
 			}
 			if (w.right == nil || w.right.color == Black) &&
 				(w.left == nil || w.left.color == Black) {
@@ -405,23 +1223,26 @@ func (s *Set) deleteFixup(x *Node, parent *Node) {
 			} else {
 				if w.left == nil || w.left.color == Black {
 					if w.right != nil {
-						w.right.color = Black
+						s.own(w, w.right).color = Black
 					}
 					w.color = Red
 					s.leftRotate(w)
-					w = parent.left
+					w = s.own(parent, parent.left)
 				}
 				w.color = parent.color
 				parent.color = Black
 				if w.left != nil {
-					w.left.color = Black
+					s.own(w, w.left).color = Black
 				}
 				s.rightRotate(parent)
 				x = s.root
 			}
 		}
 	}
-	if x != nil {
-		x.color = Black
+	if x != nil && x.color == Red {
+		if x == s.root {
+			parent = nil
+		}
+		s.own(parent, x).color = Black
 	}
 }
@@ -0,0 +1,643 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// checkSet fails t unless s is a valid tree holding exactly the keys of
+// want
+func checkSet(t *testing.T, s *Set[int], want map[int]bool) {
+	t.Helper()
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]int, 0, len(want))
+	for key := range want {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if got := s.ToSlice(); !slices.Equal(got, keys) {
+		t.Fatalf("set holds %v, want %v", got, keys)
+	}
+}
+
+// randomOps applies n random inserts and removes of keys below limit to s
+// and to its reference want
+func randomOps(rng *rand.Rand, s *Set[int], want map[int]bool, n, limit int) {
+	for i := 0; i < n; i++ {
+		key := rng.Intn(limit)
+		if rng.Intn(3) == 0 {
+			s.Remove(key)
+			delete(want, key)
+		} else {
+			s.Insert(key)
+			want[key] = true
+		}
+	}
+}
+
+func TestInsertRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := NewSet(cmp.Compare[int])
+	want := map[int]bool{}
+	for round := 0; round < 20; round++ {
+		randomOps(rng, s, want, 500, 300)
+		checkSet(t, s, want)
+	}
+	for key := range want {
+		if !s.Remove(key) {
+			t.Fatalf("Remove(%d) = false for a present key", key)
+		}
+	}
+	checkSet(t, s, map[int]bool{})
+}
+
+func TestGenericElements(t *testing.T) {
+	type point struct{ x, y int }
+	s := NewSet(func(a, b point) int {
+		if c := cmp.Compare(a.x, b.x); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.y, b.y)
+	})
+	for _, p := range []point{{2, 1}, {1, 5}, {1, 2}, {2, 1}} {
+		s.Insert(p)
+	}
+	if got, want := s.ToSlice(), []point{{1, 2}, {1, 5}, {2, 1}}; !slices.Equal(got, want) {
+		t.Fatalf("set holds %v, want %v", got, want)
+	}
+	words := NewSet(strings.Compare)
+	words.Insert("b")
+	words.Insert("a")
+	if !words.Contains("a") || words.Contains("c") || words.Size() != 2 || words.IsEmpty() {
+		t.Fatalf("string set holds %v", words.ToSlice())
+	}
+	words.Clear()
+	if !words.IsEmpty() {
+		t.Fatal("Clear left elements")
+	}
+}
+
+// intSet returns a set of ints holding keys
+func intSet(keys ...int) *Set[int] {
+	s := NewSet(cmp.Compare[int])
+	for _, key := range keys {
+		s.Insert(key)
+	}
+	return s
+}
+
+func TestIteratorBookmarks(t *testing.T) {
+	s := intSet(1, 2, 3, 4, 5)
+	it := s.Begin()
+	it.Push()
+	it.Next()
+	it.Next()
+	it.Push()
+	it.Next()
+	if it.Value() != 4 {
+		t.Fatalf("iterator at %d, want 4", it.Value())
+	}
+	if !it.Pop() || it.Value() != 3 {
+		t.Fatalf("first Pop moved to %d, want 3", it.Value())
+	}
+	if !it.Pop() || it.Value() != 1 {
+		t.Fatalf("second Pop moved to %d, want 1", it.Value())
+	}
+	if it.Pop() {
+		t.Fatal("Pop on an empty stack succeeded")
+	}
+	// A bookmark on a removed element follows the iterator
+	it.Next()
+	it.Push()
+	it.Remove()
+	if !it.Pop() || it.Value() != 3 {
+		t.Fatalf("bookmark on a removed element restored %d, want 3", it.Value())
+	}
+}
+
+func TestOnThreshold(t *testing.T) {
+	s := intSet()
+	var sizes []int
+	s.OnThreshold(3, func(s *Set[int]) { sizes = append(sizes, s.Size()) })
+	for i := 0; i < 5; i++ {
+		s.Insert(i)
+	}
+	s.Remove(0)
+	s.Remove(1)
+	s.Remove(2)
+	s.Insert(7)
+	if want := []int{3, 2, 3}; !slices.Equal(sizes, want) {
+		t.Fatalf("threshold fired at sizes %v, want %v", sizes, want)
+	}
+	s.Clear()
+	if want := []int{3, 2, 3, 0}; !slices.Equal(sizes, want) {
+		t.Fatalf("Clear fired at sizes %v, want %v", sizes, want)
+	}
+}
+
+func TestPopUntil(t *testing.T) {
+	s := intSet(5, 1, 9, 3, 7)
+	if got := s.PopUntil(5); !slices.Equal(got, []int{1, 3, 5}) {
+		t.Fatalf("PopUntil(5) = %v", got)
+	}
+	if got := s.PopUntil(0); got != nil {
+		t.Fatalf("PopUntil below the minimum = %v", got)
+	}
+	var seen []int
+	s.PopUntilFunc(8, func(key int) {
+		// Each element is already gone when fn sees it
+		if s.Contains(key) {
+			t.Fatalf("%d still in the set", key)
+		}
+		seen = append(seen, key)
+	})
+	if !slices.Equal(seen, []int{7}) {
+		t.Fatalf("PopUntilFunc(8) saw %v", seen)
+	}
+	checkSet(t, s, map[int]bool{9: true})
+	if got := s.PopUntil(100); !slices.Equal(got, []int{9}) || !s.IsEmpty() {
+		t.Fatalf("PopUntil above the maximum = %v", got)
+	}
+}
+
+func TestReplaceRange(t *testing.T) {
+	s := intSet(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	s.ReplaceRange(3, 7, []int{1, 4, 40, 5})
+	checkSet(t, s, map[int]bool{0: true, 1: true, 2: true, 4: true, 5: true, 7: true, 8: true, 9: true})
+	s.ReplaceRange(0, 100, nil)
+	checkSet(t, s, map[int]bool{})
+}
+
+func TestBounds(t *testing.T) {
+	s := intSet(10, 20, 30)
+	for _, c := range []struct {
+		key, lower, upper int
+		lowerOK, upperOK  bool
+	}{
+		{5, 10, 10, true, true},
+		{10, 10, 20, true, true},
+		{25, 30, 30, true, true},
+		{30, 30, 0, true, false},
+		{31, 0, 0, false, false},
+	} {
+		lower, upper := s.LowerBound(c.key), s.UpperBound(c.key)
+		if lower.Valid() != c.lowerOK || c.lowerOK && lower.Value() != c.lower {
+			t.Errorf("LowerBound(%d) is wrong", c.key)
+		}
+		if upper.Valid() != c.upperOK || c.upperOK && upper.Value() != c.upper {
+			t.Errorf("UpperBound(%d) is wrong", c.key)
+		}
+	}
+	// Bounds are ordinary iterators that walk on from there
+	it := s.LowerBound(15)
+	if !it.Next() || it.Value() != 30 || it.Next() {
+		t.Fatal("iterating from LowerBound went wrong")
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	s := intSet(10, 20, 30)
+	type result struct {
+		key int
+		ok  bool
+	}
+	get := func(key int, ok bool) result { return result{key, ok} }
+	for _, c := range []struct {
+		name string
+		got  result
+		want result
+	}{
+		{"Floor(20)", get(s.Floor(20)), result{20, true}},
+		{"Floor(25)", get(s.Floor(25)), result{20, true}},
+		{"Floor(5)", get(s.Floor(5)), result{}},
+		{"Ceiling(20)", get(s.Ceiling(20)), result{20, true}},
+		{"Ceiling(25)", get(s.Ceiling(25)), result{30, true}},
+		{"Ceiling(35)", get(s.Ceiling(35)), result{}},
+		{"Higher(20)", get(s.Higher(20)), result{30, true}},
+		{"Higher(30)", get(s.Higher(30)), result{}},
+		{"Lower(20)", get(s.Lower(20)), result{10, true}},
+		{"Lower(10)", get(s.Lower(10)), result{}},
+	} {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestOrderStatistics(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := intSet()
+	want := map[int]bool{}
+	randomOps(rng, s, want, 2000, 500)
+	var sorted []int
+	for key := range want {
+		sorted = append(sorted, key)
+	}
+	slices.Sort(sorted)
+	for i, key := range sorted {
+		if got, ok := s.At(i); !ok || got != key {
+			t.Fatalf("At(%d) = %d, %v, want %d", i, got, ok, key)
+		}
+		if r := s.Rank(key); r != i {
+			t.Fatalf("Rank(%d) = %d, want %d", key, r, i)
+		}
+	}
+	if _, ok := s.At(len(sorted)); ok {
+		t.Fatal("At past the end succeeded")
+	}
+	if _, ok := s.At(-1); ok {
+		t.Fatal("At(-1) succeeded")
+	}
+	if r := s.Rank(1000); r != len(sorted) {
+		t.Fatalf("Rank above the maximum = %d", r)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	s := intSet()
+	if _, ok := s.Min(); ok {
+		t.Fatal("Min of an empty set succeeded")
+	}
+	if _, ok := s.PopMax(); ok {
+		t.Fatal("PopMax of an empty set succeeded")
+	}
+	for _, key := range []int{4, 8, 1, 6} {
+		s.Insert(key)
+	}
+	if min, _ := s.Min(); min != 1 {
+		t.Fatalf("Min() = %d", min)
+	}
+	if max, _ := s.Max(); max != 8 {
+		t.Fatalf("Max() = %d", max)
+	}
+	if key, ok := s.PopMin(); !ok || key != 1 {
+		t.Fatalf("PopMin() = %d, %v", key, ok)
+	}
+	if key, ok := s.PopMax(); !ok || key != 8 {
+		t.Fatalf("PopMax() = %d, %v", key, ok)
+	}
+	checkSet(t, s, map[int]bool{4: true, 6: true})
+}
+
+// entry is keyed by id alone, so equal entries can differ in their payload
+type entry struct {
+	id      int
+	payload string
+}
+
+func compareEntries(a, b entry) int {
+	return cmp.Compare(a.id, b.id)
+}
+
+func TestInsertOrGet(t *testing.T) {
+	s := NewSet(compareEntries)
+	if got, inserted := s.InsertOrGet(entry{1, "first"}); !inserted || got.payload != "first" {
+		t.Fatalf("InsertOrGet of a new key = %v, %v", got, inserted)
+	}
+	if got, inserted := s.InsertOrGet(entry{1, "second"}); inserted || got.payload != "first" {
+		t.Fatalf("InsertOrGet of a present key = %v, %v", got, inserted)
+	}
+	if got, ok := s.Find(entry{id: 1}); !ok || got.payload != "first" {
+		t.Fatalf("Find = %v, %v", got, ok)
+	}
+	if _, ok := s.Find(entry{id: 2}); ok {
+		t.Fatal("Find of a missing key succeeded")
+	}
+
+	full := NewSetWithOptions(cmp.Compare[int], WithMaxSize[int](1))
+	full.Insert(1)
+	if got, inserted := full.InsertOrGet(2); inserted || got != 2 || full.Contains(2) {
+		t.Fatalf("InsertOrGet into a full set = %d, %v", got, inserted)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	s := NewSet(compareEntries)
+	s.Insert(entry{1, "a"})
+	if old, replaced := s.Replace(entry{1, "b"}); !replaced || old.payload != "a" {
+		t.Fatalf("Replace of a present key = %v, %v", old, replaced)
+	}
+	if _, replaced := s.Replace(entry{2, "c"}); replaced {
+		t.Fatal("Replace of a new key reported a replacement")
+	}
+	if got, _ := s.Find(entry{id: 1}); got.payload != "b" || s.Size() != 2 {
+		t.Fatalf("Find after Replace = %v", got)
+	}
+}
+
+// weighted is ordered by id and carries the total weight of its subtree,
+// kept current by the augment hook
+type weighted struct {
+	id, weight, total int
+}
+
+func TestReplaceAugmented(t *testing.T) {
+	s := NewSet(func(a, b *weighted) int { return cmp.Compare(a.id, b.id) })
+	s.augment = func(n *Node[*weighted]) {
+		n.key.total = n.key.weight
+		for _, child := range []*Node[*weighted]{n.left, n.right} {
+			if child != nil {
+				n.key.total += child.key.total
+			}
+		}
+	}
+	for i := 0; i < 100; i++ {
+		s.Insert(&weighted{id: i, weight: 1})
+	}
+	var check func(n *Node[*weighted]) int
+	check = func(n *Node[*weighted]) int {
+		if n == nil {
+			return 0
+		}
+		total := n.key.weight + check(n.left) + check(n.right)
+		if n.key.total != total {
+			t.Fatalf("subtree total of %d is %d, want %d", n.key.id, n.key.total, total)
+		}
+		return total
+	}
+	view := s.Snapshot()
+	for _, id := range []int{0, 37, 50, 99} {
+		s.Replace(&weighted{id: id, weight: 10})
+	}
+	if total := check(s.root); total != 136 {
+		t.Fatalf("total weight %d, want 136", total)
+	}
+	if view.Size() != 100 {
+		t.Fatal("Replace changed a snapshot")
+	}
+}
+
+func TestRemoveKeepsNodes(t *testing.T) {
+	// Removing a node with two children must not move its successor's
+	// key into it: every other node keeps the element it was created for
+	s := NewSet(cmp.Compare[int])
+	for i := 0; i < 256; i++ {
+		s.Insert(i)
+	}
+	nodes := map[int]*Node[int]{}
+	for i := 0; i < 256; i++ {
+		nodes[i] = s.find(i)
+	}
+	rng := rand.New(rand.NewSource(2))
+	for _, key := range rng.Perm(256)[:200] {
+		s.Remove(key)
+		delete(nodes, key)
+		for k, node := range nodes {
+			if node.key != k || s.find(k) != node {
+				t.Fatalf("node of %d moved after removing %d", k, key)
+			}
+		}
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoveRange(t *testing.T) {
+	s := NewSet(cmp.Compare[int])
+	want := map[int]bool{}
+	for i := 0; i < 50; i++ {
+		s.Insert(i)
+		if i < 10 || i >= 20 {
+			want[i] = true
+		}
+	}
+	if removed := s.RemoveRange(10, 20); removed != 10 {
+		t.Fatalf("RemoveRange removed %d, want 10", removed)
+	}
+	checkSet(t, s, want)
+}
+
+func TestRemoveIf(t *testing.T) {
+	s := intSet()
+	for i := 0; i < 100; i++ {
+		s.Insert(i)
+	}
+	if removed := s.RemoveIf(func(key int) bool { return key%4 != 1 }); removed != 75 {
+		t.Fatalf("RemoveIf removed %d, want 75", removed)
+	}
+	want := map[int]bool{}
+	for i := 1; i < 100; i += 4 {
+		want[i] = true
+	}
+	checkSet(t, s, want)
+	if intSet().RemoveIf(func(int) bool { return true }) != 0 {
+		t.Fatal("RemoveIf on an empty set removed something")
+	}
+}
+
+func TestIteratorRemove(t *testing.T) {
+	s := NewSet(cmp.Compare[int])
+	for i := 0; i < 100; i++ {
+		s.Insert(i)
+	}
+	for it := s.Begin(); it.Valid(); {
+		if it.Value()%3 == 0 {
+			it.Remove()
+		} else {
+			it.Next()
+		}
+	}
+	want := map[int]bool{}
+	for i := 0; i < 100; i++ {
+		if i%3 != 0 {
+			want[i] = true
+		}
+	}
+	checkSet(t, s, want)
+}
+
+func TestErase(t *testing.T) {
+	s := intSet(1, 2, 3, 4, 5)
+	it := s.RBegin()
+	for it.Valid() {
+		if it.Value()%2 == 0 {
+			it = s.Erase(it)
+		} else {
+			it.Next()
+		}
+	}
+	checkSet(t, s, map[int]bool{1: true, 3: true, 5: true})
+	// Erasing the last element leaves an invalid iterator
+	if next := s.Erase(s.UpperBound(3)); next.Valid() || s.Contains(5) {
+		t.Fatal("Erase of the maximum left a valid iterator")
+	}
+	if it := s.End(); it.Remove() {
+		t.Fatal("Remove on End succeeded")
+	}
+}
+
+func TestFailFastIterator(t *testing.T) {
+	s := intSet(1, 2, 3, 4)
+	it := s.Begin()
+	it.Remove()
+	// Removals through the iterator itself keep it usable
+	if !it.Next() || it.Value() != 3 {
+		t.Fatal("the iterator lost its place after its own removal")
+	}
+	s.Insert(5)
+	mustPanicWith(t, ErrConcurrentModification, func() { it.Next() })
+	mustPanicWith(t, ErrConcurrentModification, func() { it.Value() })
+
+	// A failed insert is no modification
+	it = s.Begin()
+	s.Insert(2)
+	if it.Value() != 2 {
+		t.Fatal("a no-op insert disturbed the iterator")
+	}
+}
+
+func TestCountRange(t *testing.T) {
+	s := intSet()
+	for i := 0; i < 100; i += 2 {
+		s.Insert(i)
+	}
+	for _, c := range []struct{ from, to, want int }{
+		{0, 100, 50}, {10, 20, 5}, {11, 20, 4}, {11, 21, 5}, {-50, 5, 3}, {20, 10, 0}, {7, 7, 0},
+	} {
+		if got := s.CountRange(c.from, c.to); got != c.want {
+			t.Errorf("CountRange(%d, %d) = %d, want %d", c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestBoundedIterator(t *testing.T) {
+	s := intSet(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	collect := func(it *Iterator[int]) []int {
+		var got []int
+		for ; it.Valid(); it.Next() {
+			got = append(got, it.Value())
+		}
+		return got
+	}
+	if got := collect(s.BoundedIterator(3, 7)); !slices.Equal(got, []int{3, 4, 5, 6}) {
+		t.Fatalf("BoundedIterator(3, 7) = %v", got)
+	}
+	// A limit between elements or past the end
+	if got := collect(s.BoundedIterator(8, 100)); !slices.Equal(got, []int{8, 9}) {
+		t.Fatalf("BoundedIterator(8, 100) = %v", got)
+	}
+	if it := s.BoundedIterator(5, 5); it.Valid() {
+		t.Fatal("an empty range is valid")
+	}
+
+	// A reverse iterator stops at elements not greater than the limit
+	it := s.RBegin()
+	it.SetLimit(6)
+	if got := collect(it); !slices.Equal(got, []int{9, 8, 7}) {
+		t.Fatalf("reverse iterator limited at 6 = %v", got)
+	}
+	// Stepping back from the end resumes at the last element in range
+	fwd := s.BoundedIterator(3, 7)
+	for fwd.Next() {
+	}
+	if !fwd.Prev() || fwd.Value() != 6 {
+		t.Fatal("Prev from the end did not return to 6")
+	}
+
+	// Erase keeps the limit
+	er := s.BoundedIterator(5, 7)
+	er = s.Erase(er)
+	er = s.Erase(er)
+	if er.Valid() || s.Contains(5) || s.Contains(6) || !s.Contains(7) {
+		t.Fatalf("erasing [5, 7) left %v", s.ToSlice())
+	}
+}
+
+func TestBoundedSafeIterator(t *testing.T) {
+	// The element at the limit is looked up again after a modification
+	s := NewSetWithOptions(cmp.Compare[int], WithSafeIterators[int]())
+	for i := 0; i < 10; i += 2 {
+		s.Insert(i)
+	}
+	it := s.BoundedIterator(0, 6)
+	s.Remove(6)
+	s.Insert(5)
+	var got []int
+	for ; it.Valid(); it.Next() {
+		got = append(got, it.Value())
+	}
+	if want := []int{0, 2, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("iterator saw %v, want %v", got, want)
+	}
+}
+
+func TestSafeIteratorEqualRange(t *testing.T) {
+	// A modification must not lose the end of the range, which EqualRange
+	// locates by the key rather than by a limit
+	s := NewSetWithOptions(cmp.Compare[int], WithSafeIterators[int]())
+	for i := 1; i < 10; i++ {
+		s.Insert(i)
+	}
+	it, _ := s.EqualRange(5)
+	s.Insert(100)
+	var got []int
+	for ; it.Valid(); it.Next() {
+		got = append(got, it.Value())
+	}
+	if !slices.Equal(got, []int{5}) {
+		t.Fatalf("EqualRange(5) iterated %v after an insert", got)
+	}
+
+	// Erase passes on how to find the limit again
+	er := s.BoundedIterator(2, 4)
+	er = s.Erase(er)
+	s.Insert(0)
+	s.Remove(4)
+	got = nil
+	for ; er.Valid(); er.Next() {
+		got = append(got, er.Value())
+	}
+	if !slices.Equal(got, []int{3}) {
+		t.Fatalf("erasing from [2, 4) then iterating gave %v", got)
+	}
+}
+
+func TestEqualRange(t *testing.T) {
+	s := intSet(1, 3, 5)
+	first, end := s.EqualRange(3)
+	if !first.Valid() || first.Value() != 3 || end.Value() != 5 {
+		t.Fatal("EqualRange(3) does not bracket 3")
+	}
+	if first.Next() {
+		t.Fatal("the first iterator moved past the range")
+	}
+	first, end = s.EqualRange(4)
+	if first.Valid() || end.Value() != 5 {
+		t.Fatal("EqualRange of a missing key is not empty before 5")
+	}
+	if _, end := s.EqualRange(5); end.Valid() {
+		t.Fatal("EqualRange of the maximum does not end at the end")
+	}
+}
+
+func TestEqualRangeFunc(t *testing.T) {
+	words := NewSet(strings.Compare)
+	for _, w := range []string{"ant", "bee", "bear", "bison", "cat"} {
+		words.Insert(w)
+	}
+	prefix := func(p string) func(string) int {
+		return func(w string) int {
+			if strings.HasPrefix(w, p) {
+				return 0
+			}
+			return strings.Compare(w, p)
+		}
+	}
+	var got []string
+	first, end := words.EqualRangeFunc(prefix("b"))
+	for ; first.Valid(); first.Next() {
+		got = append(got, first.Value())
+	}
+	if !slices.Equal(got, []string{"bear", "bee", "bison"}) || end.Value() != "cat" {
+		t.Fatalf("prefix b matched %v", got)
+	}
+	if first, _ := words.EqualRangeFunc(prefix("d")); first.Valid() {
+		t.Fatal("prefix d matched")
+	}
+}
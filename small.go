@@ -0,0 +1,148 @@
+package set
+
+import "sort"
+
+// smallSetLimit is the number of elements a SmallSet keeps inline before
+// it moves them into a tree
+const smallSetLimit = 32
+
+// SmallSet is a set that keeps up to 32 elements in a sorted slice and
+// only builds a red-black tree once it grows past that. Small sets thus
+// cost one allocation instead of one per element, and lookups are a binary
+// search over contiguous memory. A set that has grown stays a tree.
+type SmallSet[T any] struct {
+	items   []T
+	tree    *Set[T]
+	compare func(T, T) int
+}
+
+// NewSmallSet creates a new small set with a custom comparator
+func NewSmallSet[T any](compare func(T, T) int) *SmallSet[T] {
+	requireComparator(compare)
+	return &SmallSet[T]{compare: compare}
+}
+
+// Size returns the number of elements in the set
+func (s *SmallSet[T]) Size() int {
+	if s.tree != nil {
+		return s.tree.Size()
+	}
+	return len(s.items)
+}
+
+// IsEmpty returns true if the set has no elements
+func (s *SmallSet[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Clear removes all elements from the set and returns it to inline storage
+func (s *SmallSet[T]) Clear() {
+	clear(s.items)
+	s.items = s.items[:0]
+	s.tree = nil
+}
+
+// Insert adds a new element to the set
+func (s *SmallSet[T]) Insert(key T) bool {
+	if s.tree != nil {
+		return s.tree.Insert(key)
+	}
+	i, found := s.search(key)
+	if found {
+		return false
+	}
+	if len(s.items) == smallSetLimit {
+		s.tree = NewSet(s.compare)
+		s.tree.buildSorted(s.items)
+		s.items = nil
+		return s.tree.Insert(key)
+	}
+	if s.items == nil {
+		s.items = make([]T, 0, 4)
+	}
+	var zero T
+	s.items = append(s.items, zero)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = key
+	return true
+}
+
+// Contains checks if an element exists in the set
+func (s *SmallSet[T]) Contains(key T) bool {
+	if s.tree != nil {
+		return s.tree.Contains(key)
+	}
+	_, found := s.search(key)
+	return found
+}
+
+// Remove removes an element from the set
+func (s *SmallSet[T]) Remove(key T) bool {
+	if s.tree != nil {
+		return s.tree.Remove(key)
+	}
+	i, found := s.search(key)
+	if !found {
+		return false
+	}
+	copy(s.items[i:], s.items[i+1:])
+	var zero T
+	s.items[len(s.items)-1] = zero
+	s.items = s.items[:len(s.items)-1]
+	return true
+}
+
+// Min returns the smallest element
+func (s *SmallSet[T]) Min() (T, bool) {
+	if s.tree != nil {
+		return s.tree.Min()
+	}
+	if len(s.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.items[0], true
+}
+
+// Max returns the largest element
+func (s *SmallSet[T]) Max() (T, bool) {
+	if s.tree != nil {
+		return s.tree.Max()
+	}
+	if len(s.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// Ascend calls fn for every element in ascending order until fn returns
+// false
+func (s *SmallSet[T]) Ascend(fn func(key T) bool) {
+	if s.tree != nil {
+		s.tree.Ascend(fn)
+		return
+	}
+	for _, key := range s.items {
+		if !fn(key) {
+			return
+		}
+	}
+}
+
+// ToSlice returns the elements in ascending order
+func (s *SmallSet[T]) ToSlice() []T {
+	if s.tree != nil {
+		return s.tree.keys()
+	}
+	return append([]T(nil), s.items...)
+}
+
+// search returns the index of the first inline element not less than key
+// and whether it is equal to key
+func (s *SmallSet[T]) search(key T) (int, bool) {
+	i := sort.Search(len(s.items), func(i int) bool {
+		return s.compare(s.items[i], key) >= 0
+	})
+	return i, i < len(s.items) && s.compare(s.items[i], key) == 0
+}
@@ -0,0 +1,74 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSmallSet(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, limit := range []int{20, 100} {
+		s := NewSmallSet(cmp.Compare[int])
+		want := map[int]bool{}
+		for i := 0; i < 1000; i++ {
+			key := rng.Intn(limit)
+			if rng.Intn(3) == 0 {
+				if s.Remove(key) != want[key] {
+					t.Fatalf("Remove(%d) disagrees", key)
+				}
+				delete(want, key)
+			} else {
+				if s.Insert(key) == want[key] {
+					t.Fatalf("Insert(%d) disagrees", key)
+				}
+				want[key] = true
+			}
+		}
+		var keys []int
+		for key := range want {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		if got := s.ToSlice(); !slices.Equal(got, keys) || s.Size() != len(keys) {
+			t.Fatalf("set holds %v, want %v", got, keys)
+		}
+		// Only sets that outgrew the inline storage build a tree
+		if (s.tree != nil) != (limit > smallSetLimit) {
+			t.Fatalf("with keys below %d the tree is %v", limit, s.tree)
+		}
+		for key := 0; key < limit; key++ {
+			if s.Contains(key) != want[key] {
+				t.Fatalf("Contains(%d) disagrees", key)
+			}
+		}
+		if min, _ := s.Min(); min != keys[0] {
+			t.Fatalf("Min() = %d", min)
+		}
+		if max, _ := s.Max(); max != keys[len(keys)-1] {
+			t.Fatalf("Max() = %d", max)
+		}
+		s.Clear()
+		if !s.IsEmpty() || s.tree != nil {
+			t.Fatal("Clear did not return to inline storage")
+		}
+		if _, ok := s.Max(); ok {
+			t.Fatal("Max of an empty set succeeded")
+		}
+	}
+}
+
+func TestSmallSetAllocations(t *testing.T) {
+	s := NewSmallSet(cmp.Compare[int])
+	s.Insert(0)
+	s.Remove(0)
+	next := 0
+	allocs := testing.AllocsPerRun(smallSetLimit-1, func() {
+		s.Insert(next)
+		next++
+	})
+	if allocs != 0 {
+		t.Fatalf("inline inserts took %v allocations each", allocs)
+	}
+}
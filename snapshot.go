@@ -0,0 +1,221 @@
+package set
+
+import "sync/atomic"
+
+// SetView is an immutable view of a set as of the moment it was taken. It
+// stays consistent while the set goes on changing and can be read from
+// another goroutine without locking.
+type SetView[T any] struct {
+	set *Set[T]
+}
+
+// Snapshot returns a view of the current contents of s in O(1) time. The
+// view shares the tree of s, and writes to s copy only the nodes on their
+// path, so each write after a snapshot costs O(log n) extra allocations
+// rather than a copy of the whole tree. Snapshot itself counts as a write
+// and must not run concurrently with other calls on s, but the returned
+// view may be read concurrently with anything. Stepping an iterator over a
+// view can take O(log n) time, as the view cannot rely on parent links.
+func (s *Set[T]) Snapshot() *SetView[T] {
+	frozen := s.emptyCopy()
+	frozen.root = s.root
+	frozen.size = s.size
+	// A view never mutates, so an open scope makes iterator removals
+	// through it panic instead of writing to the shared tree
+	frozen.scopes = 1
+	s.share(frozen)
+	return &SetView[T]{set: frozen}
+}
+
+// generations hands out the generation numbers that tell the sets sharing
+// nodes apart
+var generations atomic.Uint64
+
+// share marks the tree of s as shared with other, which holds the same
+// root. Both move to new generations, so neither owns a node of the tree
+// any longer and each copies the nodes it writes to. Only s keeps the
+// parent pointers of shared nodes up to date; other finds parents by
+// searching from its root instead.
+func (s *Set[T]) share(other *Set[T]) {
+	if s.root == nil {
+		return
+	}
+	s.gen = generations.Add(1)
+	other.gen = generations.Add(1)
+	other.detached = true
+}
+
+// writable returns node, or a copy of it owned by s if the node is shared.
+// A copy is not yet linked into its parent.
+func (s *Set[T]) writable(node *Node[T]) *Node[T] {
+	if node == nil || node.gen == s.gen {
+		return node
+	}
+	c := s.newNode(node.key, node.color, node.parent)
+	c.left, c.right, c.size = node.left, node.right, node.size
+	s.setParent(c.left, c)
+	s.setParent(c.right, c)
+	return c
+}
+
+// own returns child, which is a child of the owned node parent or the
+// root if parent is nil, making sure s can write it in place. A shared
+// child is replaced by a copy in the tree.
+func (s *Set[T]) own(parent, child *Node[T]) *Node[T] {
+	c := s.writable(child)
+	if c == child {
+		return c
+	}
+	c.parent = parent
+	switch {
+	case parent == nil:
+		s.root = c
+	case parent.left == child:
+		parent.left = c
+	default:
+		parent.right = c
+	}
+	return c
+}
+
+// ownPath makes s own every node on the search path for key and returns
+// the node holding key, or nil, along with its parent, which is the last
+// node on the path if key is not found
+func (s *Set[T]) ownPath(key T) (*Node[T], *Node[T]) {
+	var parent *Node[T]
+	node := s.own(nil, s.root)
+	for node != nil {
+		cmp := s.compare(key, node.key)
+		if cmp == 0 {
+			return node, parent
+		}
+		parent = node
+		if cmp < 0 {
+			node = s.own(parent, node.left)
+		} else {
+			node = s.own(parent, node.right)
+		}
+	}
+	return nil, parent
+}
+
+// live returns the node in the tree holding the key of node, which may
+// have been replaced by a copy since it was looked up
+func (s *Set[T]) live(node *Node[T]) *Node[T] {
+	if node == nil || node.gen == s.gen {
+		return node
+	}
+	return s.find(node.key)
+}
+
+// setParent links child, which may be nil, to parent. The parent pointers
+// of shared nodes are left alone in detached sets, which never read them.
+func (s *Set[T]) setParent(child, parent *Node[T]) {
+	if child != nil && s.tracksParent(child) {
+		child.parent = parent
+	}
+}
+
+// Size returns the number of elements in the view
+func (v *SetView[T]) Size() int {
+	return v.set.size
+}
+
+// IsEmpty returns true if the view has no elements
+func (v *SetView[T]) IsEmpty() bool {
+	return v.set.size == 0
+}
+
+// Contains checks if an element exists in the view
+func (v *SetView[T]) Contains(key T) bool {
+	return v.set.find(v.set.canonical(key)) != nil
+}
+
+// Find returns the element in the view that is equal to key
+func (v *SetView[T]) Find(key T) (T, bool) {
+	return keyOf(v.set.find(v.set.canonical(key)))
+}
+
+// Min returns the smallest element
+func (v *SetView[T]) Min() (T, bool) {
+	return v.set.Min()
+}
+
+// Max returns the largest element
+func (v *SetView[T]) Max() (T, bool) {
+	return v.set.Max()
+}
+
+// Floor returns the greatest element less than or equal to key
+func (v *SetView[T]) Floor(key T) (T, bool) {
+	return v.set.Floor(key)
+}
+
+// Ceiling returns the smallest element greater than or equal to key
+func (v *SetView[T]) Ceiling(key T) (T, bool) {
+	return v.set.Ceiling(key)
+}
+
+// At returns the element at index i in ascending order
+func (v *SetView[T]) At(i int) (T, bool) {
+	return v.set.At(i)
+}
+
+// Rank returns the number of elements less than key
+func (v *SetView[T]) Rank(key T) int {
+	return v.set.Rank(key)
+}
+
+// Begin returns an iterator to the smallest element. Iterators over a
+// view are read-only; their Remove panics with ErrIterationScope.
+func (v *SetView[T]) Begin() *Iterator[T] {
+	return v.set.Begin()
+}
+
+// End returns an iterator past the largest element
+func (v *SetView[T]) End() *Iterator[T] {
+	return v.set.End()
+}
+
+// RBegin returns a reverse iterator to the largest element
+func (v *SetView[T]) RBegin() *Iterator[T] {
+	return v.set.RBegin()
+}
+
+// REnd returns a reverse iterator before the smallest element
+func (v *SetView[T]) REnd() *Iterator[T] {
+	return v.set.REnd()
+}
+
+// LowerBound returns an iterator to the first element not less than key
+func (v *SetView[T]) LowerBound(key T) *Iterator[T] {
+	return v.set.LowerBound(key)
+}
+
+// UpperBound returns an iterator to the first element greater than key
+func (v *SetView[T]) UpperBound(key T) *Iterator[T] {
+	return v.set.UpperBound(key)
+}
+
+// Ascend calls fn for every element in ascending order until fn returns
+// false
+func (v *SetView[T]) Ascend(fn func(key T) bool) {
+	v.set.Ascend(fn)
+}
+
+// Descend calls fn for every element in descending order until fn returns
+// false
+func (v *SetView[T]) Descend(fn func(key T) bool) {
+	v.set.Descend(fn)
+}
+
+// AscendRange calls fn for every element in [from, to) in ascending order
+// until fn returns false
+func (v *SetView[T]) AscendRange(from, to T, fn func(key T) bool) {
+	v.set.AscendRange(from, to, fn)
+}
+
+// ToSlice returns the elements of the view in ascending order
+func (v *SetView[T]) ToSlice() []T {
+	return v.set.keys()
+}
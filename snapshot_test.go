@@ -0,0 +1,133 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotIsolation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := NewSet(cmp.Compare[int])
+	want := map[int]bool{}
+	var views []*SetView[int]
+	var contents [][]int
+	for round := 0; round < 30; round++ {
+		randomOps(rng, s, want, 200, 150)
+		views = append(views, s.Snapshot())
+		contents = append(contents, s.ToSlice())
+	}
+	checkSet(t, s, want)
+	for i, view := range views {
+		if got := view.ToSlice(); !slices.Equal(got, contents[i]) {
+			t.Fatalf("view %d changed to %v, want %v", i, got, contents[i])
+		}
+		var backward []int
+		for it := view.RBegin(); it.Valid(); it.Next() {
+			backward = append(backward, it.Value())
+		}
+		slices.Reverse(backward)
+		if !slices.Equal(backward, contents[i]) {
+			t.Fatalf("view %d iterates backward as %v", i, backward)
+		}
+		if view.Size() != len(contents[i]) {
+			t.Fatalf("view %d has size %d, want %d", i, view.Size(), len(contents[i]))
+		}
+	}
+}
+
+func TestSnapshotWritesCopyPath(t *testing.T) {
+	s := NewSet(cmp.Compare[int])
+	for i := 0; i < 1<<16; i++ {
+		s.Insert(2 * i)
+	}
+	key := 1
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Snapshot()
+		s.Insert(key)
+		key += 2
+	})
+	// The path to a leaf is under 2·log n = 32 nodes, plus the rotations
+	// and the snapshot itself
+	if allocs > 60 {
+		t.Fatalf("a write after a snapshot took %v allocations", allocs)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSnapshotIterators(t *testing.T) {
+	s := NewSet(cmp.Compare[int])
+	for i := 0; i < 20; i++ {
+		s.Insert(i)
+	}
+	view := s.Snapshot()
+	it := view.LowerBound(5)
+	s.RemoveRange(0, 10)
+	var got []int
+	for ; it.Valid() && it.Value() < 12; it.Next() {
+		got = append(got, it.Value())
+	}
+	if want := []int{5, 6, 7, 8, 9, 10, 11}; !slices.Equal(got, want) {
+		t.Fatalf("view iterator saw %v, want %v", got, want)
+	}
+	defer func() {
+		if r := recover(); r != ErrIterationScope {
+			t.Fatalf("Remove through a view: recovered %v", r)
+		}
+	}()
+	view.Begin().Remove()
+}
+
+func TestSnapshotConcurrentReaders(t *testing.T) {
+	s := NewSet(cmp.Compare[int])
+	for i := 0; i < 1000; i++ {
+		s.Insert(i)
+	}
+	view := s.Snapshot()
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := 0; round < 20; round++ {
+				n := 0
+				view.Ascend(func(int) bool {
+					n++
+					return true
+				})
+				if n != 1000 {
+					t.Errorf("reader saw %d elements, want 1000", n)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 1000; i += 2 {
+		s.Remove(i)
+	}
+	wg.Wait()
+	if s.Size() != 500 {
+		t.Fatalf("size %d, want 500", s.Size())
+	}
+}
+
+func TestSafeIteratorAfterSnapshot(t *testing.T) {
+	s := NewSetWithOptions(cmp.Compare[int], WithSafeIterators[int]())
+	for i := 0; i < 10; i++ {
+		s.Insert(i)
+	}
+	it := s.LowerBound(4)
+	s.Snapshot()
+	s.Remove(2)
+	var got []int
+	for ; it.Valid(); it.Next() {
+		got = append(got, it.Value())
+	}
+	if want := []int{4, 5, 6, 7, 8, 9}; !slices.Equal(got, want) {
+		t.Fatalf("safe iterator saw %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package set
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Stats describes the shape of a set's tree
+type Stats struct {
+	// Nodes is the number of nodes, equal to the size of the set
+	Nodes int
+	// RedNodes is the number of red nodes
+	RedNodes int
+	// Height is the number of nodes on the longest root-to-leaf path
+	Height int
+	// BlackHeight is the number of black nodes on every root-to-leaf path
+	BlackHeight int
+	// Depths counts the nodes at each depth, starting with the root at 0
+	Depths []int
+	// AverageDepth is the mean depth of all nodes
+	AverageDepth float64
+	// LeftRotations and RightRotations count the rebalancing rotations
+	// done by this set since it was created
+	LeftRotations, RightRotations uint64
+	// NodeBytes estimates the memory held by the nodes, including keys
+	// stored inline but not memory the keys point to
+	NodeBytes int64
+	// Inserts, Removes and Lookups count the calls to Insert, Remove and
+	// Contains or Find, and Inserted, Removed and Hits the calls among
+	// them that found or changed an element. They are only counted for
+	// sets created WithStatsCollection.
+	Inserts, Removes, Lookups uint64
+	Inserted, Removed, Hits   uint64
+}
+
+// WithStatsCollection makes the set count its Insert, Remove, Contains and
+// Find calls and their outcomes, reported by Stats. The counters are
+// atomic, so readers sharing the set under a read lock can count safely.
+func WithStatsCollection[T any]() Option[T] {
+	return func(s *Set[T]) {
+		s.counters = &opCounters{}
+	}
+}
+
+// opCounters counts calls and successful calls per Operation
+type opCounters struct {
+	calls, hits [3]atomic.Uint64
+}
+
+func (c *opCounters) record(op Operation, ok bool) {
+	c.calls[op].Add(1)
+	if ok {
+		c.hits[op].Add(1)
+	}
+}
+
+// Height returns the number of nodes on the longest path from the root to
+// a leaf, which is at most 2·log₂(n+1)
+func (s *Set[T]) Height() int {
+	var height func(node *Node[T]) int
+	height = func(node *Node[T]) int {
+		if node == nil {
+			return 0
+		}
+		return 1 + max(height(node.left), height(node.right))
+	}
+	return height(s.root)
+}
+
+// BlackHeight returns the number of black nodes on every path from the
+// root to a leaf
+func (s *Set[T]) BlackHeight() int {
+	return blackHeight(s.root)
+}
+
+// Stats walks the tree and reports its shape. It takes O(n) time.
+func (s *Set[T]) Stats() Stats {
+	st := Stats{
+		Nodes:          s.size,
+		BlackHeight:    blackHeight(s.root),
+		LeftRotations:  s.rotations[0],
+		RightRotations: s.rotations[1],
+		NodeBytes:      int64(s.size) * int64(unsafe.Sizeof(Node[T]{})),
+	}
+	total := 0
+	var walk func(node *Node[T], depth int)
+	walk = func(node *Node[T], depth int) {
+		if node == nil {
+			return
+		}
+		if depth == len(st.Depths) {
+			st.Depths = append(st.Depths, 0)
+		}
+		st.Depths[depth]++
+		total += depth
+		if node.color == Red {
+			st.RedNodes++
+		}
+		walk(node.left, depth+1)
+		walk(node.right, depth+1)
+	}
+	walk(s.root, 0)
+	st.Height = len(st.Depths)
+	if c := s.counters; c != nil {
+		st.Inserts, st.Inserted = c.calls[OpInsert].Load(), c.hits[OpInsert].Load()
+		st.Removes, st.Removed = c.calls[OpRemove].Load(), c.hits[OpRemove].Load()
+		st.Lookups, st.Hits = c.calls[OpContains].Load(), c.hits[OpContains].Load()
+	}
+	if s.size > 0 {
+		st.AverageDepth = float64(total) / float64(s.size)
+	}
+	return st
+}
+
+// MemoryFootprint estimates the bytes held by s: the set header and its
+// nodes, which include each key's inline value, plus keySizer(key) for
+// every key to account for memory the keys point to, such as string or
+// slice contents. A nil keySizer counts the inline part only, in O(1)
+// time; otherwise every key is visited.
+func (s *Set[T]) MemoryFootprint(keySizer func(key T) int) int64 {
+	total := int64(unsafe.Sizeof(*s)) + int64(s.size)*int64(unsafe.Sizeof(Node[T]{}))
+	if keySizer != nil {
+		ascend(s.root, func(key T) bool {
+			total += int64(keySizer(key))
+			return true
+		})
+	}
+	return total
+}
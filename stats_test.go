@@ -0,0 +1,78 @@
+package set
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestStats(t *testing.T) {
+	s := intSet()
+	for i := 0; i < 1000; i++ {
+		s.Insert(i)
+	}
+	st := s.Stats()
+	if st.Nodes != 1000 || st.Height != s.Height() || st.BlackHeight != s.BlackHeight() {
+		t.Fatalf("Stats() = %+v", st)
+	}
+	if limit := 2 * math.Log2(1001); float64(st.Height) > limit {
+		t.Fatalf("height %d exceeds %.1f", st.Height, limit)
+	}
+	nodes, depths := 0, 0
+	for depth, n := range st.Depths {
+		nodes += n
+		depths += depth * n
+	}
+	if nodes != 1000 || len(st.Depths) != st.Height || st.AverageDepth != float64(depths)/1000 {
+		t.Fatalf("depth distribution %v, average %v", st.Depths, st.AverageDepth)
+	}
+	red := 0
+	s.Ascend(func(key int) bool {
+		if s.find(key).color == Red {
+			red++
+		}
+		return true
+	})
+	if st.RedNodes != red {
+		t.Fatalf("RedNodes = %d, want %d", st.RedNodes, red)
+	}
+	// Ascending inserts only ever lean right
+	if st.LeftRotations == 0 || st.RightRotations != 0 {
+		t.Fatalf("%d left and %d right rotations", st.LeftRotations, st.RightRotations)
+	}
+
+	// A perfect tree of seven nodes is all black
+	perfect := intSet()
+	perfect.BuildFromSorted([]int{1, 2, 3, 4, 5, 6, 7})
+	st = perfect.Stats()
+	if st.Height != 3 || st.BlackHeight != 3 || st.RedNodes != 0 || !slices.Equal(st.Depths, []int{1, 2, 4}) {
+		t.Fatalf("Stats of a perfect tree = %+v", st)
+	}
+	if st := intSet().Stats(); st.Height != 0 || st.AverageDepth != 0 || st.Depths != nil {
+		t.Fatalf("Stats of an empty set = %+v", st)
+	}
+}
+
+func TestMemoryFootprint(t *testing.T) {
+	s := NewSet(strings.Compare)
+	base := s.MemoryFootprint(nil)
+	if base != int64(unsafe.Sizeof(*s)) {
+		t.Fatalf("empty set footprint %d", base)
+	}
+	for _, w := range []string{"a", "bb", "ccc"} {
+		s.Insert(w)
+	}
+	node := int64(unsafe.Sizeof(Node[string]{}))
+	if got := s.MemoryFootprint(nil); got != base+3*node {
+		t.Fatalf("inline footprint %d, want %d", got, base+3*node)
+	}
+	byLen := func(key string) int { return len(key) }
+	if got := s.MemoryFootprint(byLen); got != base+3*node+6 {
+		t.Fatalf("footprint with key contents %d, want %d", got, base+3*node+6)
+	}
+	if got := s.Stats().NodeBytes; got != 3*node {
+		t.Fatalf("NodeBytes = %d", got)
+	}
+}
@@ -0,0 +1,26 @@
+package set
+
+import "context"
+
+// Stream sends the elements of s in ascending order on the returned
+// channel, which has room for buffer elements, and closes it after the
+// last one or once ctx is done. The elements are read from a snapshot, so
+// s may be modified while the stream is being consumed without affecting
+// it.
+func (s *Set[T]) Stream(ctx context.Context, buffer int) <-chan T {
+	view := s.Snapshot()
+	ch := make(chan T, buffer)
+	go func() {
+		defer close(ch)
+		done := ctx.Done()
+		view.Ascend(func(key T) bool {
+			select {
+			case ch <- key:
+				return true
+			case <-done:
+				return false
+			}
+		})
+	}()
+	return ch
+}
@@ -0,0 +1,43 @@
+package set
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	s := intSet(3, 1, 2)
+	ch := s.Stream(context.Background(), 0)
+	// The stream reads a snapshot, so later changes are not seen
+	s.Insert(0)
+	s.Remove(2)
+	var got []int
+	for key := range ch {
+		got = append(got, key)
+	}
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("Stream = %v", got)
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	s := intSet()
+	for i := 0; i < 1000; i++ {
+		s.Insert(i)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := s.Stream(ctx, 0)
+	if key := <-ch; key != 0 {
+		t.Fatalf("first streamed element is %d", key)
+	}
+	cancel()
+	n := 0
+	for range ch {
+		n++
+	}
+	// At most the element already offered when ctx was cancelled follows
+	if n > 1 {
+		t.Fatalf("%d elements were streamed after cancelling", n)
+	}
+}
@@ -0,0 +1,382 @@
+package set
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// SyncSet is a Set safe for concurrent use, guarding every operation with
+// a read-write mutex. It wraps the lookups, updates, bulk operations and
+// iteration of Set; iteration runs over an O(1) snapshot, so callbacks may
+// freely modify the SyncSet. Left out are the methods that hand out the
+// tree or tie it to another set: the views such as HeadSet and
+// Descending, Fork, Locked, Subscribe, OnThreshold, Erase, the set
+// algebra and Join, Split and Merge. Snapshot returns an independent Set
+// for those.
+type SyncSet[T any] struct {
+	mu          sync.RWMutex
+	set         *Set[T]
+	lockTimeout atomic.Int64 // how long the Try methods wait, in nanoseconds
+}
+
+// NewSyncSet creates a new concurrency-safe set with a custom comparator
+func NewSyncSet[T any](compare func(T, T) int, opts ...Option[T]) *SyncSet[T] {
+	return &SyncSet[T]{set: NewSetWithOptions(compare, opts...)}
+}
+
+// Size returns the number of elements in the set
+func (s *SyncSet[T]) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Size()
+}
+
+// IsEmpty returns true if the set has no elements
+func (s *SyncSet[T]) IsEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.IsEmpty()
+}
+
+// Clear removes all elements from the set
+func (s *SyncSet[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set.Clear()
+}
+
+// Insert adds a new element to the set
+func (s *SyncSet[T]) Insert(key T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.Insert(key)
+}
+
+// Contains checks if an element exists in the set. With a loader
+// configured a miss may insert, so the lookups take the write lock
+// instead.
+func (s *SyncSet[T]) Contains(key T) bool {
+	defer s.lookupLock()()
+	return s.set.Contains(key)
+}
+
+// Remove removes an element from the set
+func (s *SyncSet[T]) Remove(key T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.Remove(key)
+}
+
+// ContainsAll returns true if every one of keys is in the set
+func (s *SyncSet[T]) ContainsAll(keys ...T) bool {
+	defer s.lookupLock()()
+	return s.set.ContainsAll(keys...)
+}
+
+// ContainsAny returns true if at least one of keys is in the set
+func (s *SyncSet[T]) ContainsAny(keys ...T) bool {
+	defer s.lookupLock()()
+	return s.set.ContainsAny(keys...)
+}
+
+// Find returns the stored element equal to key
+func (s *SyncSet[T]) Find(key T) (T, bool) {
+	defer s.lookupLock()()
+	return s.set.Find(key)
+}
+
+// FindE is like Find but reports failure as an error, as Set.FindE does
+func (s *SyncSet[T]) FindE(key T) (T, error) {
+	defer s.lookupLock()()
+	return s.set.FindE(key)
+}
+
+// InsertE is like Insert but reports failure as an error, as Set.InsertE
+// does
+func (s *SyncSet[T]) InsertE(key T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.InsertE(key)
+}
+
+// InsertOrGet adds key unless an equal element is present and returns the
+// stored element, with true if key was inserted
+func (s *SyncSet[T]) InsertOrGet(key T) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.InsertOrGet(key)
+}
+
+// Replace stores key, overwriting an equal element if there is one, and
+// returns the element it replaced
+func (s *SyncSet[T]) Replace(key T) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.Replace(key)
+}
+
+// AddAll adds keys under a single lock and returns how many were not yet
+// present
+func (s *SyncSet[T]) AddAll(keys ...T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.AddAll(keys...)
+}
+
+// InsertBatch adds the elements of items under a single lock and returns
+// how many were not yet present
+func (s *SyncSet[T]) InsertBatch(items []T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.InsertBatch(items)
+}
+
+// RemoveE is like Remove but reports failure as an error, as Set.RemoveE
+// does
+func (s *SyncSet[T]) RemoveE(key T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.RemoveE(key)
+}
+
+// RemoveAll removes keys under a single lock and returns how many were
+// present
+func (s *SyncSet[T]) RemoveAll(keys ...T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.RemoveAll(keys...)
+}
+
+// RetainAll removes every element that is not one of keys and returns how
+// many were removed
+func (s *SyncSet[T]) RetainAll(keys ...T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.RetainAll(keys...)
+}
+
+// RemoveRange removes all elements in [from, to) and returns how many were
+// removed
+func (s *SyncSet[T]) RemoveRange(from, to T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.RemoveRange(from, to)
+}
+
+// RemoveIf removes all elements for which pred returns true and returns
+// how many were removed. pred runs under the write lock and must not call
+// back into s.
+func (s *SyncSet[T]) RemoveIf(pred func(key T) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.RemoveIf(pred)
+}
+
+// PopMin removes and returns the smallest element
+func (s *SyncSet[T]) PopMin() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.PopMin()
+}
+
+// PopMax removes and returns the largest element
+func (s *SyncSet[T]) PopMax() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.PopMax()
+}
+
+// PopUntil removes and returns all elements less than or equal to threshold
+func (s *SyncSet[T]) PopUntil(threshold T) []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.PopUntil(threshold)
+}
+
+// ReplaceRange replaces the elements in [from, to) under a single lock
+func (s *SyncSet[T]) ReplaceRange(from, to T, replacement []T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set.ReplaceRange(from, to, replacement)
+}
+
+// Floor returns the greatest element less than or equal to key
+func (s *SyncSet[T]) Floor(key T) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Floor(key)
+}
+
+// Ceiling returns the smallest element greater than or equal to key
+func (s *SyncSet[T]) Ceiling(key T) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Ceiling(key)
+}
+
+// Higher returns the smallest element strictly greater than key
+func (s *SyncSet[T]) Higher(key T) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Higher(key)
+}
+
+// Lower returns the greatest element strictly less than key
+func (s *SyncSet[T]) Lower(key T) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Lower(key)
+}
+
+// Min returns the smallest element
+func (s *SyncSet[T]) Min() (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Min()
+}
+
+// Max returns the largest element
+func (s *SyncSet[T]) Max() (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Max()
+}
+
+// At returns the element at index i in ascending order
+func (s *SyncSet[T]) At(i int) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.At(i)
+}
+
+// Rank returns the number of elements less than key
+func (s *SyncSet[T]) Rank(key T) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.Rank(key)
+}
+
+// CountRange returns the number of elements in [from, to)
+func (s *SyncSet[T]) CountRange(from, to T) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.CountRange(from, to)
+}
+
+// Snapshot returns an independent copy of the set taken under the read lock
+func (s *SyncSet[T]) Snapshot() *Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snapshot := s.set.emptyCopy()
+	snapshot.buildSorted(s.set.keys())
+	return snapshot
+}
+
+// Keys returns the elements in ascending order
+func (s *SyncSet[T]) Keys() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.keys()
+}
+
+// ToSlice returns the elements in ascending order
+func (s *SyncSet[T]) ToSlice() []T {
+	return s.Keys()
+}
+
+// String returns the elements in ascending order, formatted as by Set
+func (s *SyncSet[T]) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.String()
+}
+
+// MarshalJSON encodes the set as a JSON array of its elements in order
+func (s *SyncSet[T]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a
+// JSON array
+func (s *SyncSet[T]) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.UnmarshalJSON(data)
+}
+
+// View calls fn with a read-only view of the set under a single read
+// lock, so a series of lookups and range queries sees one consistent state
+// without locking for each. It costs no copying, but the view is only
+// valid until fn returns; take a Snapshot to keep one. fn must not write
+// to s.
+func (s *SyncSet[T]) View(fn func(v *SetView[T])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	view := s.set.emptyCopy()
+	view.root, view.size = s.set.root, s.set.size
+	// As in Snapshot, the open scope keeps iterators from removing
+	view.scopes = 1
+	fn(&SetView[T]{set: view})
+}
+
+// Ascend calls fn for every element of a snapshot in ascending order until
+// fn returns false
+func (s *SyncSet[T]) Ascend(fn func(key T) bool) {
+	s.frozen().Ascend(fn)
+}
+
+// Descend calls fn for every element of a snapshot in descending order
+// until fn returns false
+func (s *SyncSet[T]) Descend(fn func(key T) bool) {
+	s.frozen().Descend(fn)
+}
+
+// AscendRange calls fn for every element of a snapshot of [from, to) in
+// ascending order until fn returns false
+func (s *SyncSet[T]) AscendRange(from, to T, fn func(key T) bool) {
+	s.frozen().AscendRange(from, to, fn)
+}
+
+// Begin returns an iterator to the smallest element of a snapshot. The
+// iterator is read-only; its Remove panics with ErrIterationScope.
+func (s *SyncSet[T]) Begin() *Iterator[T] {
+	return s.frozen().Begin()
+}
+
+// RBegin returns a reverse iterator to the largest element of a snapshot
+func (s *SyncSet[T]) RBegin() *Iterator[T] {
+	return s.frozen().RBegin()
+}
+
+// LowerBound returns an iterator to the first element of a snapshot not
+// less than key
+func (s *SyncSet[T]) LowerBound(key T) *Iterator[T] {
+	return s.frozen().LowerBound(key)
+}
+
+// UpperBound returns an iterator to the first element of a snapshot
+// greater than key
+func (s *SyncSet[T]) UpperBound(key T) *Iterator[T] {
+	return s.frozen().UpperBound(key)
+}
+
+// frozen takes an O(1) snapshot of the set. Taking one counts as a write
+// to the set, so it needs the write lock, but only for a moment.
+func (s *SyncSet[T]) frozen() *SetView[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.Snapshot()
+}
+
+// lookupLock takes the lock needed by a lookup and returns its unlock: the
+// read lock, or the write lock if a loader may insert on a miss
+func (s *SyncSet[T]) lookupLock() func() {
+	if s.set.loader != nil {
+		s.mu.Lock()
+		return s.mu.Unlock
+	}
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
@@ -0,0 +1,118 @@
+package set
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncSetLockTimeout(t *testing.T) {
+	s := NewSyncSet(cmp.Compare[int])
+	if ok, err := s.TryInsert(1); !ok || err != nil {
+		t.Fatalf("TryInsert on a free lock = %v, %v", ok, err)
+	}
+	s.mu.Lock()
+	if _, err := s.TryContains(1); !errors.Is(err, ErrLockTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("TryContains on a held lock: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.InsertContext(ctx, 2); !errors.Is(err, context.Canceled) {
+		t.Fatalf("InsertContext with a canceled context: %v", err)
+	}
+	s.SetLockTimeout(time.Second)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		s.mu.Unlock()
+	}()
+	if ok, err := s.TryRemove(1); !ok || err != nil {
+		t.Fatalf("TryRemove once the lock is released = %v, %v", ok, err)
+	}
+}
+
+func TestSyncSetView(t *testing.T) {
+	s := NewSyncSet(cmp.Compare[int])
+	s.AddAll(1, 2, 3, 4)
+	s.View(func(v *SetView[int]) {
+		if v.Size() != 4 || !v.Contains(3) || v.Rank(3) != 2 {
+			t.Fatalf("view of %v is inconsistent", v.ToSlice())
+		}
+	})
+}
+
+func TestSyncSetViewConsistent(t *testing.T) {
+	// Writers wait for a View, so its queries agree with each other
+	s := NewSyncSet(cmp.Compare[int])
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 2000; i++ {
+			s.Insert(i % 100)
+			s.Remove((7 * i) % 100)
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		s.View(func(v *SetView[int]) {
+			n := 0
+			for it := v.Begin(); it.Valid(); it.Next() {
+				n++
+			}
+			if n != v.Size() {
+				t.Errorf("view iterates over %d elements but has size %d", n, v.Size())
+			}
+		})
+	}
+	<-done
+}
+
+func TestSyncSetConcurrent(t *testing.T) {
+	s := NewSyncSet(cmp.Compare[int])
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := (7*i + g) % 300
+				s.Insert(key)
+				s.Contains(key + 1)
+				s.Rank(key)
+				s.Ascend(func(k int) bool { return k < 50 })
+				if i%100 == 0 {
+					s.RemoveRange(100, 120)
+					s.PopMin()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := s.Snapshot().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncSetIterationWrites(t *testing.T) {
+	// Iteration runs over a snapshot, so callbacks may write
+	s := NewSyncSet(cmp.Compare[int])
+	s.AddAll(1, 2, 3)
+	s.Ascend(func(key int) bool {
+		s.Insert(10 * key)
+		return true
+	})
+	if got, want := s.Keys(), []int{1, 2, 3, 10, 20, 30}; !slices.Equal(got, want) {
+		t.Fatalf("set holds %v, want %v", got, want)
+	}
+	it := s.LowerBound(3)
+	s.Remove(10)
+	var got []int
+	for ; it.Valid(); it.Next() {
+		got = append(got, it.Value())
+	}
+	if want := []int{3, 10, 20, 30}; !slices.Equal(got, want) {
+		t.Fatalf("iterator saw %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package set
+
+// TopK keeps the k largest elements offered to it, as ordered by its
+// comparator. It is a BoundedSet with the EvictMin policy that also
+// remembers its smallest element while full, so a candidate too small to
+// enter is rejected with a single comparison.
+type TopK[T any] struct {
+	bounded *BoundedSet[T]
+	floor   *Node[T] // smallest element while the set is full
+}
+
+// NewTopK creates a new top-k tracker with a custom comparator
+func NewTopK[T any](compare func(T, T) int, k int, opts ...Option[T]) *TopK[T] {
+	return &TopK[T]{bounded: NewBoundedSet(compare, k, EvictMin, opts...)}
+}
+
+// K returns the number of elements kept
+func (t *TopK[T]) K() int {
+	return t.bounded.MaxSize()
+}
+
+// Len returns the number of elements currently kept
+func (t *TopK[T]) Len() int {
+	return t.bounded.Size()
+}
+
+// Offer considers item for the top k and reports whether it was kept.
+// When keeping it pushed another element out, that element is returned as
+// evicted; otherwise evicted is the zero value.
+func (t *TopK[T]) Offer(item T) (evicted T, kept bool) {
+	s := t.bounded.set
+	if t.floor != nil && s.compare(s.canonical(item), t.floor.key) <= 0 {
+		return evicted, false
+	}
+	evicted, _, kept = t.bounded.offer(item)
+	if kept && t.bounded.IsFull() {
+		t.floor = s.minimum(s.root)
+	}
+	return evicted, kept
+}
+
+// Threshold returns the smallest element kept, which a candidate must
+// exceed to enter once the top k are full
+func (t *TopK[T]) Threshold() (T, bool) {
+	return t.bounded.Min()
+}
+
+// Best returns the largest element kept
+func (t *TopK[T]) Best() (T, bool) {
+	return t.bounded.Max()
+}
+
+// Sorted returns the elements kept from the largest to the smallest
+func (t *TopK[T]) Sorted() []T {
+	items := make([]T, 0, t.Len())
+	t.bounded.Descend(func(key T) bool {
+		items = append(items, key)
+		return true
+	})
+	return items
+}
+
+// Descend calls fn for the elements kept from the largest to the smallest
+// until fn returns false
+func (t *TopK[T]) Descend(fn func(key T) bool) {
+	t.bounded.Descend(fn)
+}
+
+// Reset discards all elements kept
+func (t *TopK[T]) Reset() {
+	t.bounded.Clear()
+	t.floor = nil
+}
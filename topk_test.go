@@ -0,0 +1,53 @@
+package set
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	top := NewTopK(cmp.Compare[int], 3)
+	for _, key := range []int{5, 1, 9} {
+		if _, kept := top.Offer(key); !kept {
+			t.Fatalf("%d was rejected from a partly filled top 3", key)
+		}
+	}
+	if _, kept := top.Offer(1); kept {
+		t.Fatal("the threshold itself was kept")
+	}
+	if evicted, kept := top.Offer(7); !kept || evicted != 1 {
+		t.Fatalf("Offer(7) = %d, %v", evicted, kept)
+	}
+	if got := top.Sorted(); !slices.Equal(got, []int{9, 7, 5}) {
+		t.Fatalf("Sorted = %v", got)
+	}
+	if lo, _ := top.Threshold(); lo != 5 {
+		t.Fatalf("Threshold = %d", lo)
+	}
+	if hi, _ := top.Best(); hi != 9 {
+		t.Fatalf("Best = %d", hi)
+	}
+	top.Reset()
+	if _, kept := top.Offer(0); !kept || top.Len() != 1 {
+		t.Fatal("Reset kept the old threshold")
+	}
+}
+
+func TestTopKRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	top := NewTopK(cmp.Compare[int], 20)
+	var all []int
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(100000)
+		top.Offer(key)
+		all = append(all, key)
+	}
+	slices.Sort(all)
+	all = slices.Compact(all)
+	slices.Reverse(all)
+	if got := top.Sorted(); !slices.Equal(got, all[:20]) {
+		t.Fatalf("kept %v, want %v", got, all[:20])
+	}
+}
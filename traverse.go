@@ -0,0 +1,54 @@
+package set
+
+// AscendRange calls fn for every element in [from, to) in ascending order
+// until fn returns false
+func (s *Set[T]) AscendRange(from, to T, fn func(key T) bool) {
+	to = s.canonical(to)
+	for node := s.lowerBound(s.canonical(from)); node != nil; node = s.successor(node) {
+		if s.compare(node.key, to) >= 0 || !fn(node.key) {
+			return
+		}
+	}
+}
+
+// ForEach calls fn for every element in ascending order until fn returns
+// false. It is an alias for Ascend.
+func (s *Set[T]) ForEach(fn func(key T) bool) {
+	ascend(s.root, fn)
+}
+
+// Ascend calls fn for every element in ascending order until fn returns
+// false. The tree is walked directly, without allocating an iterator.
+func (s *Set[T]) Ascend(fn func(key T) bool) {
+	ascend(s.root, fn)
+}
+
+// Descend calls fn for every element in descending order until fn returns
+// false
+func (s *Set[T]) Descend(fn func(key T) bool) {
+	descend(s.root, fn)
+}
+
+// ascend walks the subtree rooted at node in order and reports whether the
+// walk ran to completion
+func ascend[T any](node *Node[T], fn func(T) bool) bool {
+	for node != nil {
+		if !ascend(node.left, fn) || !fn(node.key) {
+			return false
+		}
+		node = node.right
+	}
+	return true
+}
+
+// descend walks the subtree rooted at node in reverse order and reports
+// whether the walk ran to completion
+func descend[T any](node *Node[T], fn func(T) bool) bool {
+	for node != nil {
+		if !descend(node.right, fn) || !fn(node.key) {
+			return false
+		}
+		node = node.left
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAscendRange(t *testing.T) {
+	s := intSet(1, 3, 5, 7, 9)
+	collect := func(from, to, limit int) []int {
+		var got []int
+		s.AscendRange(from, to, func(key int) bool {
+			got = append(got, key)
+			return len(got) < limit
+		})
+		return got
+	}
+	for _, c := range []struct {
+		from, to, limit int
+		want            []int
+	}{
+		{3, 9, 10, []int{3, 5, 7}},
+		{2, 8, 10, []int{3, 5, 7}},
+		{0, 100, 2, []int{1, 3}},
+		{5, 5, 10, nil},
+		{9, 3, 10, nil},
+	} {
+		if got := collect(c.from, c.to, c.limit); !slices.Equal(got, c.want) {
+			t.Errorf("AscendRange(%d, %d) with limit %d = %v, want %v", c.from, c.to, c.limit, got, c.want)
+		}
+	}
+}
+
+func TestTraversal(t *testing.T) {
+	s := intSet(4, 2, 6, 1, 3, 5, 7)
+	walk := func(traverse func(func(int) bool), limit int) []int {
+		var got []int
+		traverse(func(key int) bool {
+			got = append(got, key)
+			return len(got) < limit
+		})
+		return got
+	}
+	if got := walk(s.Ascend, 10); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Fatalf("Ascend = %v", got)
+	}
+	if got := walk(s.ForEach, 3); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("ForEach stopped at %v", got)
+	}
+	if got := walk(s.Descend, 10); !slices.Equal(got, []int{7, 6, 5, 4, 3, 2, 1}) {
+		t.Fatalf("Descend = %v", got)
+	}
+	if got := walk(s.Descend, 4); !slices.Equal(got, []int{7, 6, 5, 4}) {
+		t.Fatalf("Descend stopped at %v", got)
+	}
+	if got := walk(intSet().Ascend, 10); got != nil {
+		t.Fatalf("Ascend of an empty set = %v", got)
+	}
+}
@@ -0,0 +1,81 @@
+package set
+
+import "fmt"
+
+// Validate checks the invariants of the tree: the root is black, no red
+// node has a red child, every path has the same number of black nodes,
+// keys are strictly ordered under the comparator, and parent links and
+// subtree sizes are consistent. It returns an error naming the key at the
+// first violation found, or nil. It takes O(n) time and is meant for tests
+// and debugging.
+func (s *Set[T]) Validate() error {
+	if s.root == nil {
+		if s.size != 0 {
+			return fmt.Errorf("set: empty tree but size is %d", s.size)
+		}
+		return nil
+	}
+	if s.root.parent != nil && s.tracksParent(s.root) {
+		return fmt.Errorf("set: root %v has a parent", s.root.key)
+	}
+	if s.root.color != Black {
+		return fmt.Errorf("set: root %v is red", s.root.key)
+	}
+	count, _, err := s.validateNode(s.root, nil, nil)
+	if err != nil {
+		return err
+	}
+	if count != s.size {
+		return fmt.Errorf("set: tree holds %d nodes but size is %d", count, s.size)
+	}
+	return nil
+}
+
+// validateNode checks the subtree rooted at n, whose keys must lie strictly
+// between the keys of lo and hi when those are set
+func (s *Set[T]) validateNode(n, lo, hi *Node[T]) (count, blackHeight int, err error) {
+	if n == nil {
+		return 0, 1, nil
+	}
+	if lo != nil && s.compare(n.key, lo.key) <= 0 {
+		return 0, 0, fmt.Errorf("set: key %v is not greater than ancestor %v", n.key, lo.key)
+	}
+	if hi != nil && s.compare(n.key, hi.key) >= 0 {
+		return 0, 0, fmt.Errorf("set: key %v is not less than ancestor %v", n.key, hi.key)
+	}
+	for _, child := range []*Node[T]{n.left, n.right} {
+		if child == nil {
+			continue
+		}
+		if child.parent != n && s.tracksParent(child) {
+			return 0, 0, fmt.Errorf("set: key %v has a wrong parent link", child.key)
+		}
+		if n.color == Red && child.color == Red {
+			return 0, 0, fmt.Errorf("set: red key %v has red child %v", n.key, child.key)
+		}
+	}
+	lc, lh, err := s.validateNode(n.left, lo, n)
+	if err != nil {
+		return 0, 0, err
+	}
+	rc, rh, err := s.validateNode(n.right, n, hi)
+	if err != nil {
+		return 0, 0, err
+	}
+	if n.size != lc+rc+1 {
+		return 0, 0, fmt.Errorf("set: key %v has subtree size %d but holds %d nodes", n.key, n.size, lc+rc+1)
+	}
+	if lh != rh {
+		return 0, 0, fmt.Errorf("set: key %v has black height %d on the left and %d on the right", n.key, lh, rh)
+	}
+	if n.color == Black {
+		lh++
+	}
+	return lc + rc + 1, lh, nil
+}
+
+// tracksParent reports whether s keeps the parent pointer of node up to
+// date, which detached sets don't do for shared nodes
+func (s *Set[T]) tracksParent(node *Node[T]) bool {
+	return !s.detached || node.gen == s.gen
+}
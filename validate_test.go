@@ -0,0 +1,38 @@
+package set
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	if err := intSet().Validate(); err != nil {
+		t.Fatalf("empty set: %v", err)
+	}
+	for _, c := range []struct {
+		name    string
+		corrupt func(s *Set[int])
+		want    string
+	}{
+		{"red root", func(s *Set[int]) { s.root.color = Red }, "root 4 is red"},
+		{"root parent", func(s *Set[int]) { s.root.parent = s.root.left }, "has a parent"},
+		{"red child", func(s *Set[int]) { s.root.left.color, s.root.left.left.color = Red, Red }, "has red child"},
+		{"parent link", func(s *Set[int]) { s.root.left.left.parent = s.root }, "wrong parent link"},
+		{"order", func(s *Set[int]) { s.root.left.left.key = 5 }, "key 5 is not less than ancestor 2"},
+		{"subtree size", func(s *Set[int]) { s.root.right.size = 7 }, "subtree size 7"},
+		{"black height", func(s *Set[int]) { s.root.left.color = Red }, "black height"},
+		{"size", func(s *Set[int]) { s.size++ }, "size is 8"},
+	} {
+		s := intSet()
+		if err := s.BuildFromSorted([]int{1, 2, 3, 4, 5, 6, 7}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Validate(); err != nil {
+			t.Fatalf("%s: the intact tree failed: %v", c.name, err)
+		}
+		c.corrupt(s)
+		if err := s.Validate(); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: Validate() = %v, want %q", c.name, err, c.want)
+		}
+	}
+}
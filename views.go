@@ -0,0 +1,389 @@
+package set
+
+import "errors"
+
+// DescendingSet is a live view of a set in reverse order. It reads and
+// writes the underlying tree directly, so changes through either are
+// visible in both; only the direction of ordered operations is flipped.
+type DescendingSet[T any] struct {
+	set *Set[T]
+}
+
+// Descending returns a view of s in descending order
+func (s *Set[T]) Descending() *DescendingSet[T] {
+	return &DescendingSet[T]{set: s}
+}
+
+// Ascending returns the underlying set
+func (d *DescendingSet[T]) Ascending() *Set[T] {
+	return d.set
+}
+
+// Size returns the number of elements in the set
+func (d *DescendingSet[T]) Size() int {
+	return d.set.Size()
+}
+
+// IsEmpty returns true if the set has no elements
+func (d *DescendingSet[T]) IsEmpty() bool {
+	return d.set.IsEmpty()
+}
+
+// Contains checks if an element exists in the set
+func (d *DescendingSet[T]) Contains(key T) bool {
+	return d.set.Contains(key)
+}
+
+// Insert adds a new element to the set
+func (d *DescendingSet[T]) Insert(key T) bool {
+	return d.set.Insert(key)
+}
+
+// Remove removes an element from the set
+func (d *DescendingSet[T]) Remove(key T) bool {
+	return d.set.Remove(key)
+}
+
+// Min returns the first element in descending order, the largest one
+func (d *DescendingSet[T]) Min() (T, bool) {
+	return d.set.Max()
+}
+
+// Max returns the last element in descending order, the smallest one
+func (d *DescendingSet[T]) Max() (T, bool) {
+	return d.set.Min()
+}
+
+// Floor returns the last element in descending order that does not come
+// after key, which is the smallest element greater than or equal to it
+func (d *DescendingSet[T]) Floor(key T) (T, bool) {
+	return d.set.Ceiling(key)
+}
+
+// Ceiling returns the first element in descending order that does not come
+// before key, which is the greatest element less than or equal to it
+func (d *DescendingSet[T]) Ceiling(key T) (T, bool) {
+	return d.set.Floor(key)
+}
+
+// Higher returns the first element in descending order that comes after
+// key
+func (d *DescendingSet[T]) Higher(key T) (T, bool) {
+	return d.set.Lower(key)
+}
+
+// Lower returns the last element in descending order that comes before key
+func (d *DescendingSet[T]) Lower(key T) (T, bool) {
+	return d.set.Higher(key)
+}
+
+// At returns the element at index i in descending order
+func (d *DescendingSet[T]) At(i int) (T, bool) {
+	if i < 0 {
+		return keyOf[T](nil)
+	}
+	return d.set.At(d.set.size - 1 - i)
+}
+
+// Rank returns the number of elements that come before key in descending
+// order
+func (d *DescendingSet[T]) Rank(key T) int {
+	s := d.set
+	key = s.canonical(key)
+	rank := s.size - s.rank(key)
+	if s.find(key) != nil {
+		rank--
+	}
+	return rank
+}
+
+// Begin returns an iterator to the largest element that moves towards
+// smaller ones
+func (d *DescendingSet[T]) Begin() *Iterator[T] {
+	return d.set.RBegin()
+}
+
+// End returns an iterator past the smallest element
+func (d *DescendingSet[T]) End() *Iterator[T] {
+	return d.set.REnd()
+}
+
+// RBegin returns an iterator to the smallest element that moves towards
+// larger ones
+func (d *DescendingSet[T]) RBegin() *Iterator[T] {
+	return d.set.Begin()
+}
+
+// REnd returns an iterator past the largest element in that direction
+func (d *DescendingSet[T]) REnd() *Iterator[T] {
+	return d.set.End()
+}
+
+// LowerBound returns an iterator to the first element in descending order
+// that does not come before key
+func (d *DescendingSet[T]) LowerBound(key T) *Iterator[T] {
+	s := d.set
+	return s.iterator(s.floor(s.canonical(key), true), true)
+}
+
+// UpperBound returns an iterator to the first element in descending order
+// that comes after key
+func (d *DescendingSet[T]) UpperBound(key T) *Iterator[T] {
+	s := d.set
+	return s.iterator(s.floor(s.canonical(key), false), true)
+}
+
+// Ascend calls fn for every element in descending order until fn returns
+// false
+func (d *DescendingSet[T]) Ascend(fn func(key T) bool) {
+	d.set.Descend(fn)
+}
+
+// Descend calls fn for every element in ascending order until fn returns
+// false
+func (d *DescendingSet[T]) Descend(fn func(key T) bool) {
+	d.set.Ascend(fn)
+}
+
+// AscendRange calls fn, in descending order, for every element from from
+// down to but excluding to, until fn returns false
+func (d *DescendingSet[T]) AscendRange(from, to T, fn func(key T) bool) {
+	s := d.set
+	to = s.canonical(to)
+	for node := s.floor(s.canonical(from), true); node != nil; node = s.predecessor(node) {
+		if s.compare(node.key, to) <= 0 || !fn(node.key) {
+			return
+		}
+	}
+}
+
+// RangeView is a live view of the elements of a set within key bounds.
+// Reads and writes go to the underlying set; inserting a key outside the
+// bounds panics with ErrOutOfRange.
+type RangeView[T any] struct {
+	set          *Set[T]
+	lo, hi       T
+	hasLo, hasHi bool
+}
+
+// ErrOutOfRange is the panic value raised when a key outside the bounds of
+// a RangeView is inserted through it
+var ErrOutOfRange = errors.New("set: key outside view bounds")
+
+// HeadSet returns a live view of the elements less than to
+func (s *Set[T]) HeadSet(to T) *RangeView[T] {
+	return &RangeView[T]{set: s, hi: s.canonical(to), hasHi: true}
+}
+
+// TailSet returns a live view of the elements greater than or equal to
+// from
+func (s *Set[T]) TailSet(from T) *RangeView[T] {
+	return &RangeView[T]{set: s, lo: s.canonical(from), hasLo: true}
+}
+
+// SubSet returns a live view of the elements in [from, to)
+func (s *Set[T]) SubSet(from, to T) *RangeView[T] {
+	return &RangeView[T]{set: s, lo: s.canonical(from), hi: s.canonical(to), hasLo: true, hasHi: true}
+}
+
+// HeadSet returns a live view of the elements of v less than to
+func (v *RangeView[T]) HeadSet(to T) *RangeView[T] {
+	result := *v
+	result.clip(v.set.canonical(to), false)
+	return &result
+}
+
+// TailSet returns a live view of the elements of v greater than or equal to
+// from
+func (v *RangeView[T]) TailSet(from T) *RangeView[T] {
+	result := *v
+	result.clip(v.set.canonical(from), true)
+	return &result
+}
+
+// SubSet returns a live view of the elements of v in [from, to)
+func (v *RangeView[T]) SubSet(from, to T) *RangeView[T] {
+	result := *v
+	result.clip(v.set.canonical(from), true)
+	result.clip(v.set.canonical(to), false)
+	return &result
+}
+
+// Size returns the number of elements in the view, counted in O(log n)
+// from subtree sizes
+func (v *RangeView[T]) Size() int {
+	s := v.set
+	lo, hi := 0, s.size
+	if v.hasLo {
+		lo = s.rank(v.lo)
+	}
+	if v.hasHi {
+		hi = s.rank(v.hi)
+	}
+	return max(hi-lo, 0)
+}
+
+// IsEmpty returns true if the view has no elements
+func (v *RangeView[T]) IsEmpty() bool {
+	return v.first() == nil
+}
+
+// Contains checks if an element within the bounds exists in the set
+func (v *RangeView[T]) Contains(key T) bool {
+	return v.contains(v.set.canonical(key)) && v.set.Contains(key)
+}
+
+// Insert adds a new element to the set. It panics with ErrOutOfRange if key
+// lies outside the bounds of the view.
+func (v *RangeView[T]) Insert(key T) bool {
+	if !v.contains(v.set.canonical(key)) {
+		panic(ErrOutOfRange)
+	}
+	return v.set.Insert(key)
+}
+
+// Remove removes an element within the bounds from the set
+func (v *RangeView[T]) Remove(key T) bool {
+	return v.contains(v.set.canonical(key)) && v.set.Remove(key)
+}
+
+// Clear removes all elements within the bounds from the set
+func (v *RangeView[T]) Clear() {
+	s := v.set
+	s.checkMutable()
+	for node := v.first(); node != nil && v.belowHi(node.key); {
+		next := s.successor(node)
+		s.removeNode(node)
+		node = s.live(next)
+	}
+}
+
+// Min returns the smallest element in the view
+func (v *RangeView[T]) Min() (T, bool) {
+	return keyOf(v.first())
+}
+
+// Max returns the largest element in the view
+func (v *RangeView[T]) Max() (T, bool) {
+	return keyOf(v.last())
+}
+
+// Floor returns the greatest element in the view less than or equal to key
+func (v *RangeView[T]) Floor(key T) (T, bool) {
+	return keyOf(v.below(v.set.floor(v.set.canonical(key), true)))
+}
+
+// Ceiling returns the smallest element in the view greater than or equal
+// to key
+func (v *RangeView[T]) Ceiling(key T) (T, bool) {
+	return keyOf(v.above(v.set.lowerBound(v.set.canonical(key))))
+}
+
+// Higher returns the smallest element in the view strictly greater than key
+func (v *RangeView[T]) Higher(key T) (T, bool) {
+	return keyOf(v.above(v.set.upperBound(v.set.canonical(key))))
+}
+
+// Lower returns the greatest element in the view strictly less than key
+func (v *RangeView[T]) Lower(key T) (T, bool) {
+	return keyOf(v.below(v.set.floor(v.set.canonical(key), false)))
+}
+
+// Ascend calls fn for every element in the view in ascending order until
+// fn returns false
+func (v *RangeView[T]) Ascend(fn func(key T) bool) {
+	for node := v.first(); node != nil && v.belowHi(node.key); node = v.set.successor(node) {
+		if !fn(node.key) {
+			return
+		}
+	}
+}
+
+// Descend calls fn for every element in the view in descending order
+// until fn returns false
+func (v *RangeView[T]) Descend(fn func(key T) bool) {
+	for node := v.last(); node != nil && v.aboveLo(node.key); node = v.set.predecessor(node) {
+		if !fn(node.key) {
+			return
+		}
+	}
+}
+
+// ToSlice returns the elements of the view in ascending order
+func (v *RangeView[T]) ToSlice() []T {
+	var keys []T
+	v.Ascend(func(key T) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// clip narrows the bounds of v to start at key, or to end before it
+func (v *RangeView[T]) clip(key T, lower bool) {
+	cmp := v.set.compare
+	if lower {
+		if !v.hasLo || cmp(key, v.lo) > 0 {
+			v.lo, v.hasLo = key, true
+		}
+	} else if !v.hasHi || cmp(key, v.hi) < 0 {
+		v.hi, v.hasHi = key, true
+	}
+}
+
+func (v *RangeView[T]) aboveLo(key T) bool {
+	return !v.hasLo || v.set.compare(key, v.lo) >= 0
+}
+
+func (v *RangeView[T]) belowHi(key T) bool {
+	return !v.hasHi || v.set.compare(key, v.hi) < 0
+}
+
+func (v *RangeView[T]) contains(key T) bool {
+	return v.aboveLo(key) && v.belowHi(key)
+}
+
+// first returns the node of the smallest element in the view
+func (v *RangeView[T]) first() *Node[T] {
+	if v.hasLo {
+		return v.above(v.set.lowerBound(v.lo))
+	}
+	return v.above(v.set.first())
+}
+
+// last returns the node of the largest element in the view
+func (v *RangeView[T]) last() *Node[T] {
+	if v.hasHi {
+		return v.below(v.set.floor(v.hi, false))
+	}
+	if v.set.root == nil {
+		return nil
+	}
+	return v.below(v.set.maximum(v.set.root))
+}
+
+// above clamps node, the result of a search moving upwards, to the view:
+// a node below the view is replaced by the first one, and a node past it
+// by nil
+func (v *RangeView[T]) above(node *Node[T]) *Node[T] {
+	if node != nil && !v.aboveLo(node.key) {
+		node = v.set.lowerBound(v.lo)
+	}
+	if node == nil || !v.belowHi(node.key) {
+		return nil
+	}
+	return node
+}
+
+// below clamps node, the result of a search moving downwards, to the view:
+// a node past the view is replaced by the last one, and a node below it
+// by nil
+func (v *RangeView[T]) below(node *Node[T]) *Node[T] {
+	if node != nil && !v.belowHi(node.key) {
+		node = v.set.floor(v.hi, false)
+	}
+	if node == nil || !v.aboveLo(node.key) {
+		return nil
+	}
+	return node
+}
@@ -0,0 +1,130 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDescending(t *testing.T) {
+	s := intSet(10, 20, 30, 40)
+	d := s.Descending()
+	if d.Ascending() != s {
+		t.Fatal("Ascending does not return the set")
+	}
+	var got []int
+	for it := d.Begin(); it.Valid(); it.Next() {
+		got = append(got, it.Value())
+	}
+	if !slices.Equal(got, []int{40, 30, 20, 10}) {
+		t.Fatalf("descending iteration = %v", got)
+	}
+	type result struct {
+		key int
+		ok  bool
+	}
+	get := func(key int, ok bool) result { return result{key, ok} }
+	for _, c := range []struct {
+		name      string
+		got, want result
+	}{
+		{"Min", get(d.Min()), result{40, true}},
+		{"Max", get(d.Max()), result{10, true}},
+		{"Floor(25)", get(d.Floor(25)), result{30, true}},
+		{"Ceiling(25)", get(d.Ceiling(25)), result{20, true}},
+		{"Higher(30)", get(d.Higher(30)), result{20, true}},
+		{"Lower(30)", get(d.Lower(30)), result{40, true}},
+		{"Higher(10)", get(d.Higher(10)), result{}},
+		{"At(1)", get(d.At(1)), result{30, true}},
+		{"At(-1)", get(d.At(-1)), result{}},
+	} {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if d.Rank(30) != 1 || d.Rank(25) != 2 || d.Rank(5) != 4 {
+		t.Fatalf("Rank(30) = %d, Rank(25) = %d, Rank(5) = %d", d.Rank(30), d.Rank(25), d.Rank(5))
+	}
+	if it := d.LowerBound(25); it.Value() != 20 || !it.Next() || it.Value() != 10 {
+		t.Fatal("LowerBound does not walk downwards")
+	}
+	if it := d.UpperBound(20); it.Value() != 10 {
+		t.Fatal("UpperBound(20) is not on 10")
+	}
+	got = nil
+	d.AscendRange(35, 10, func(key int) bool {
+		got = append(got, key)
+		return true
+	})
+	if !slices.Equal(got, []int{30, 20}) {
+		t.Fatalf("AscendRange(35, 10) = %v", got)
+	}
+	// The view is live in both directions
+	d.Insert(50)
+	s.Remove(10)
+	if max, _ := d.Max(); max != 20 || !s.Contains(50) || d.Size() != 4 {
+		t.Fatal("the view and the set disagree")
+	}
+}
+
+func TestRangeView(t *testing.T) {
+	s := intSet(10, 20, 30, 40, 50)
+	sub := s.SubSet(20, 45)
+	if got := sub.ToSlice(); !slices.Equal(got, []int{20, 30, 40}) || sub.Size() != 3 {
+		t.Fatalf("SubSet(20, 45) = %v", got)
+	}
+	if got := s.HeadSet(30).ToSlice(); !slices.Equal(got, []int{10, 20}) {
+		t.Fatalf("HeadSet(30) = %v", got)
+	}
+	if got := s.TailSet(30).ToSlice(); !slices.Equal(got, []int{30, 40, 50}) {
+		t.Fatalf("TailSet(30) = %v", got)
+	}
+	// Nested views only ever narrow
+	if got := sub.SubSet(0, 35).ToSlice(); !slices.Equal(got, []int{20, 30}) {
+		t.Fatalf("nested SubSet = %v", got)
+	}
+	if empty := sub.TailSet(100); !empty.IsEmpty() || empty.Size() != 0 {
+		t.Fatal("a view past the bounds is not empty")
+	}
+
+	type result struct {
+		key int
+		ok  bool
+	}
+	get := func(key int, ok bool) result { return result{key, ok} }
+	for _, c := range []struct {
+		name      string
+		got, want result
+	}{
+		{"Min", get(sub.Min()), result{20, true}},
+		{"Max", get(sub.Max()), result{40, true}},
+		{"Floor(100)", get(sub.Floor(100)), result{40, true}},
+		{"Floor(15)", get(sub.Floor(15)), result{}},
+		{"Ceiling(0)", get(sub.Ceiling(0)), result{20, true}},
+		{"Ceiling(41)", get(sub.Ceiling(41)), result{}},
+		{"Higher(20)", get(sub.Higher(20)), result{30, true}},
+		{"Lower(20)", get(sub.Lower(20)), result{}},
+	} {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	var desc []int
+	sub.Descend(func(key int) bool {
+		desc = append(desc, key)
+		return true
+	})
+	if !slices.Equal(desc, []int{40, 30, 20}) {
+		t.Fatalf("Descend = %v", desc)
+	}
+
+	// Writes go through to the set and stay within the bounds
+	if !sub.Insert(25) || !s.Contains(25) {
+		t.Fatal("Insert through the view did not reach the set")
+	}
+	if sub.Remove(10) || sub.Contains(10) || !s.Contains(10) {
+		t.Fatal("the view reached outside its bounds")
+	}
+	mustPanicWith(t, ErrOutOfRange, func() { sub.Insert(45) })
+	sub.Clear()
+	checkSet(t, s, map[int]bool{10: true, 50: true})
+}